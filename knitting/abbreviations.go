@@ -0,0 +1,327 @@
+package knitting
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Written motifs may expand to at most this many stitches, so counts like
+// "k99999999" or deeply nested repeats can't use up all the memory
+const MaxAbbreviatedLength = 10000
+
+// Abbreviations that are written with a stitch count, e.g. "k2" or
+// "sl1 wyif". The keys are the abbreviation without the count, followed by
+// the modifier if there is one.
+var countedAbbreviations = map[string]KnitStitch{
+	"k":      Knit,
+	"p":      Purl,
+	"ktbl":   KnitTwisted,
+	"ptbl":   PurlTwisted,
+	"sl":     SlipWyib,
+	"slwyib": SlipWyib,
+	"slwyif": SlipWyif,
+}
+
+// Abbreviations that are written once per stitch, e.g. "yo, yo"
+var singleAbbreviations = map[string]KnitStitch{
+	"yo":    YarnOver,
+	"k2tog": K2tog,
+	"p2tog": P2tog,
+	"ssk":   Ssk,
+	"ssp":   Ssp,
+	"sk2p":  Sk2p,
+	"sssp":  Sssp,
+}
+
+// Words that can follow a counted abbreviation to modify it, as in
+// "k1 tbl"
+var modifiers = map[string]bool{
+	"tbl":  true,
+	"wyib": true,
+	"wyif": true,
+}
+
+// Splits e.g. "sl2wyif" into "sl", "2", "wyif"
+var countedPattern = regexp.MustCompile(`^([a-z]+?)(\d*)(tbl|wyib|wyif)?$`)
+
+// A word or symbol from a written motif, e.g. "k2", "(" or "x3"
+type token struct {
+	text string
+	// 1-based position of the token within the input string, measured
+	// in runes
+	position int
+}
+
+func (t token) String() string {
+	return fmt.Sprintf("%q at position %d", t.text, t.position)
+}
+
+func isSeparator(r rune) bool {
+	return unicode.IsSpace(r) || r == ','
+}
+
+func isParenthesis(r rune) bool {
+	return r == '(' || r == ')'
+}
+
+// Split a written motif into tokens. Tokens are separated by whitespace
+// or commas, and parentheses are always tokens of their own.
+func tokenize(motif string) []token {
+	tokens := []token{}
+	runes := []rune(motif)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		if isSeparator(r) {
+			i++
+			continue
+		}
+
+		if isParenthesis(r) {
+			tokens = append(tokens, token{string(r), i + 1})
+			i++
+			continue
+		}
+
+		start := i
+		for i < len(runes) && !isSeparator(runes[i]) && !isParenthesis(runes[i]) {
+			i++
+		}
+		tokens = append(tokens, token{string(runes[start:i]), start + 1})
+	}
+
+	return tokens
+}
+
+// Parse a count such as the "3" in "k3". An empty count means 1
+func parseCount(digits string, t token) (int, error) {
+	if digits == "" {
+		return 1, nil
+	}
+
+	count, err := strconv.Atoi(digits)
+	if err != nil || count < 1 {
+		return 0, fmt.Errorf("count must be a positive integer in %v", t)
+	}
+
+	return count, nil
+}
+
+func tooLong(t token) error {
+	return fmt.Errorf("motif expands to more than %d stitches at %v", MaxAbbreviatedLength, t)
+}
+
+func repeatStitch(stitch KnitStitch, count int) Motif {
+	result := make(Motif, count)
+	for i := range result {
+		result[i] = stitch
+	}
+
+	return result
+}
+
+// Parse a repeat count token like "x3" that follows a group
+func parseRepeatToken(t token) (int, bool, error) {
+	text := strings.ToLower(t.text)
+	if !strings.HasPrefix(text, "x") {
+		return 0, false, nil
+	}
+
+	count, err := parseCount(text[1:], t)
+	if err != nil || text == "x" {
+		return 0, false, fmt.Errorf("repeat count must be a positive integer in %v", t)
+	}
+
+	return count, true, nil
+}
+
+// Recursive descent parser for written motifs
+type motifParser struct {
+	tokens []token
+	next   int
+}
+
+func (parser *motifParser) done() bool {
+	return parser.next >= len(parser.tokens)
+}
+
+func (parser *motifParser) peek() token {
+	return parser.tokens[parser.next]
+}
+
+// Parse a sequence of stitches and groups until the end of the input or
+// a closing parenthesis. The closing parenthesis is not consumed.
+func (parser *motifParser) parseSequence() (Motif, error) {
+	result := Motif{}
+	for !parser.done() {
+		t := parser.peek()
+		switch {
+		case t.text == ")":
+			return result, nil
+		case t.text == "(":
+			group, err := parser.parseGroup()
+			if err != nil {
+				return Motif{}, err
+			}
+			result = append(result, group...)
+			if len(result) > MaxAbbreviatedLength {
+				return Motif{}, tooLong(t)
+			}
+		default:
+			if _, isRepeat, _ := parseRepeatToken(t); isRepeat {
+				return Motif{}, fmt.Errorf("repeat must follow a group in parentheses, got %v", t)
+			}
+
+			stitches, err := parser.parseStitch()
+			if err != nil {
+				return Motif{}, err
+			}
+			result = append(result, stitches...)
+			if len(result) > MaxAbbreviatedLength {
+				return Motif{}, tooLong(t)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// Parse a single stitch like "k2", "yo" or "sl1 wyif" into a run of
+// stitches. A modifier like "tbl" may be attached to the stitch or written
+// as the next token.
+func (parser *motifParser) parseStitch() (Motif, error) {
+	t := parser.peek()
+	parser.next++
+
+	text := strings.ToLower(t.text)
+	if stitch, ok := singleAbbreviations[text]; ok {
+		return Motif{stitch}, nil
+	}
+
+	parts := countedPattern.FindStringSubmatch(text)
+	if parts == nil {
+		return Motif{}, fmt.Errorf("unknown stitch %v", t)
+	}
+	abbreviation, digits, modifier := parts[1], parts[2], parts[3]
+
+	if modifier == "" && !parser.done() {
+		next := strings.ToLower(parser.peek().text)
+		if modifiers[next] {
+			modifier = next
+			parser.next++
+		}
+	}
+
+	stitch, ok := countedAbbreviations[abbreviation+modifier]
+	if !ok {
+		return Motif{}, fmt.Errorf("unknown stitch %v", t)
+	}
+
+	count, err := parseCount(digits, t)
+	if err != nil {
+		return Motif{}, err
+	}
+
+	if count > MaxAbbreviatedLength {
+		return Motif{}, tooLong(t)
+	}
+
+	return repeatStitch(stitch, count), nil
+}
+
+// Parse a group like "(k1 p1)x3". The group is repeated once if no
+// repeat count is given.
+func (parser *motifParser) parseGroup() (Motif, error) {
+	open := parser.peek()
+	parser.next++
+
+	body, err := parser.parseSequence()
+	if err != nil {
+		return Motif{}, err
+	}
+
+	if parser.done() {
+		return Motif{}, fmt.Errorf("unclosed %v", open)
+	}
+	parser.next++
+
+	if len(body) == 0 {
+		return Motif{}, fmt.Errorf("empty group %v", open)
+	}
+
+	repeats := 1
+	if !parser.done() {
+		count, isRepeat, err := parseRepeatToken(parser.peek())
+		if err != nil {
+			return Motif{}, err
+		}
+
+		if isRepeat {
+			if count > MaxAbbreviatedLength/len(body) {
+				return Motif{}, tooLong(parser.peek())
+			}
+			repeats = count
+			parser.next++
+		}
+	}
+
+	result := make(Motif, 0, len(body)*repeats)
+	for i := 0; i < repeats; i++ {
+		result = append(result, body...)
+	}
+
+	return result, nil
+}
+
+// Parse a motif written in standard knitting abbreviations, e.g.
+// "k2 p3 k1 p2". Stitches may be separated by spaces or commas. Besides
+// knits and purls, this understands twisted stitches ("k1 tbl", "p1 tbl"),
+// slipped stitches ("sl1 wyib", "sl1 wyif"), "yo", "k2tog" and "p2tog".
+// Parentheses group stitches that can be repeated, e.g. "(k1 p1)x3 k2",
+// and groups can be nested. The expanded motif can be at most
+// MaxAbbreviatedLength stitches long.
+//
+// Errors include the offending token and its position in the input.
+func ParseAbbreviatedMotif(motif string) (Motif, error) {
+	parser := motifParser{tokens: tokenize(motif)}
+	if parser.done() {
+		return Motif{}, errors.New("motif must not be empty")
+	}
+
+	result, err := parser.parseSequence()
+	if err != nil {
+		return Motif{}, err
+	}
+
+	if !parser.done() {
+		return Motif{}, fmt.Errorf("unexpected %v", parser.peek())
+	}
+
+	return result, nil
+}
+
+// Parse a motif written either as a string of stitch symbols like
+// "vv---v--" (see ParseMotif) or as abbreviations like "k2 p3 k1 p2"
+// (see ParseAbbreviatedMotif).
+func ParseAnyMotif(motif string) (Motif, error) {
+	result, err := ParseMotif(motif)
+	if err == nil {
+		return result, nil
+	}
+
+	abbreviated, abbreviatedErr := ParseAbbreviatedMotif(motif)
+	if abbreviatedErr == nil {
+		return abbreviated, nil
+	}
+
+	// Only report the abbreviation error if the motif looks like it was
+	// meant to be written out.
+	if strings.ContainsAny(motif, "0123456789(), ") {
+		return Motif{}, abbreviatedErr
+	}
+
+	return Motif{}, err
+}
@@ -0,0 +1,112 @@
+package knitting
+
+import (
+	"testing"
+
+	"github.com/ptrgags/mindless-stitchcraft/checks"
+)
+
+func TestParseAbbreviatedMotif(t *testing.T) {
+	t.Run("empty motif returns error", func(t *testing.T) {
+		result, err := ParseAbbreviatedMotif("  ")
+
+		checks.CheckHasError(t, result, err, "motif must not be empty")
+	})
+
+	t.Run("parses valid motifs", func(t *testing.T) {
+		cases := []struct {
+			label    string
+			motif    string
+			expected string
+		}{
+			{"runs of stitches", "k2 p3 k1 p2", "vv---v--"},
+			{"missing count means one stitch", "k p k", "v-v"},
+			{"commas separate stitches", "k2, p1,k1", "vv-v"},
+			{"uppercase abbreviations", "K2 P1", "vv-"},
+			{"repeated group", "(k1 p1)x3 k2", "v-v-v-vv"},
+			{"repeat count separated by space", "(k1 p1) x2", "v-v-"},
+			{"group without repeat count", "(k2 p1) k1", "vv-v"},
+			{"nested groups", "((k1 p1)x2 p2)x2", "v-v---v-v---"},
+			{"twisted stitches", "k2 tbl p1 tbl", "bbq"},
+			{"modifier attached to stitch", "k1tbl sl2wyif", "bff"},
+			{"slipped stitches", "sl1 wyib sl1 wyif sl1", "sfs"},
+			{"yarn overs and decreases", "k1 yo k2tog p2tog", "vo/z"},
+			{"left-leaning decreases", "yo ssk yo sk2p ssp sssp", "o\\o^nm"},
+		}
+
+		for _, tc := range cases {
+			t.Run(tc.label, func(t *testing.T) {
+				result, err := ParseAbbreviatedMotif(tc.motif)
+
+				expected, _ := ParseMotif(tc.expected)
+				checks.CheckHasNoError(t, result, err)
+				checks.CheckSlicesEqual(t, result, expected)
+			})
+		}
+	})
+
+	t.Run("errors point at the offending token", func(t *testing.T) {
+		cases := []struct {
+			label         string
+			motif         string
+			expectedError string
+		}{
+			{"unknown stitch", "k2 q3", `unknown stitch "q3" at position 4`},
+			{"zero count", "k0 p1", `count must be a positive integer in "k0" at position 1`},
+			{"unclosed group", "k1 (k1 p1", `unclosed "(" at position 4`},
+			{"unmatched closing parenthesis", "k1 p1) k2", `unexpected ")" at position 6`},
+			{"repeat without group", "k1 x3", `repeat must follow a group in parentheses, got "x3" at position 4`},
+			{"invalid repeat count", "(k1)x0", `repeat count must be a positive integer in "x0" at position 5`},
+			{"empty group", "k1 ()x2", `empty group "(" at position 4`},
+			{"modifier that does not apply", "yo tbl", `unknown stitch "tbl" at position 4`},
+			{"uncounted stitch with count", "yo2", `unknown stitch "yo2" at position 1`},
+			{"count that is too large", "k99999999999", `motif expands to more than 10000 stitches at "k99999999999" at position 1`},
+			{"repeat that is too large", "(k1 p1)x5001", `motif expands to more than 10000 stitches at "x5001" at position 8`},
+			{"nested repeats that are too large", "((k1)x100)x101", `motif expands to more than 10000 stitches at "x101" at position 11`},
+			{"stitches that add up to too many", "k5000 p5000 k1", `motif expands to more than 10000 stitches at "k1" at position 13`},
+		}
+
+		for _, tc := range cases {
+			t.Run(tc.label, func(t *testing.T) {
+				result, err := ParseAbbreviatedMotif(tc.motif)
+
+				checks.CheckHasError(t, result, err, tc.expectedError)
+			})
+		}
+	})
+}
+
+func TestParseAnyMotif(t *testing.T) {
+	t.Run("parses stitch symbols", func(t *testing.T) {
+		result, err := ParseAnyMotif("vv-")
+
+		checks.CheckHasNoError(t, result, err)
+		checks.CheckSlicesEqual(t, result, Motif{Knit, Knit, Purl})
+	})
+
+	t.Run("parses abbreviations", func(t *testing.T) {
+		result, err := ParseAnyMotif("k2 p1")
+
+		checks.CheckHasNoError(t, result, err)
+		checks.CheckSlicesEqual(t, result, Motif{Knit, Knit, Purl})
+	})
+
+	t.Run("parses a single abbreviation", func(t *testing.T) {
+		result, err := ParseAnyMotif("p")
+
+		checks.CheckHasNoError(t, result, err)
+		checks.CheckSlicesEqual(t, result, Motif{Purl})
+	})
+
+	t.Run("invalid symbols report the symbol error", func(t *testing.T) {
+		result, err := ParseAnyMotif("vv--🧶")
+
+		checks.CheckHasError(t, result, err, "stitch 🧶 must be a knit (v) or purl (-)")
+	})
+
+	t.Run("invalid abbreviations report the abbreviation error", func(t *testing.T) {
+		result, err := ParseAnyMotif("k2 z1")
+
+		checks.CheckHasError(t, result, err, `unknown stitch "z1" at position 4`)
+	})
+}
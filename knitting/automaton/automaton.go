@@ -0,0 +1,234 @@
+package automaton
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ptrgags/mindless-stitchcraft/knitting"
+)
+
+// Stop looking for a cycle after this many rows
+const MaxRows = 10000
+
+// Totalistic rules look at most this many cells to each side
+const MaxRadius = 3
+
+// A rule for computing the next row of a 1D cellular automaton. Purls are
+// live cells and knits are dead cells.
+type Rule struct {
+	// How many cells to each side of a cell affect its next state
+	Radius int
+	// Compute the next state of a cell given its neighborhood, listed from
+	// left to right
+	next func(neighborhood []bool) bool
+}
+
+// An elementary rule like rule 30, 90 or 110. Bit i of the rule number is
+// the next state for the neighborhood whose left, center and right cells
+// spell i in binary.
+func Elementary(number int) (Rule, error) {
+	if number < 0 || number > 255 {
+		return Rule{}, fmt.Errorf("elementary rule must be between 0 and 255, got %d", number)
+	}
+
+	next := func(neighborhood []bool) bool {
+		index := 0
+		for _, alive := range neighborhood {
+			index <<= 1
+			if alive {
+				index |= 1
+			}
+		}
+
+		return number&(1<<index) != 0
+	}
+
+	return Rule{1, next}, nil
+}
+
+// A totalistic rule, where the next state only depends on how many cells
+// of the neighborhood are alive. Bit i of the code is the next state when i
+// cells are alive.
+func Totalistic(code int, radius int) (Rule, error) {
+	if radius < 1 || radius > MaxRadius {
+		return Rule{}, fmt.Errorf("radius must be between 1 and %d", MaxRadius)
+	}
+
+	// The neighborhood has 2 * radius + 1 cells, so there are 2 * radius + 2
+	// possible counts
+	limit := 1 << (2*radius + 2)
+	if code < 0 || code >= limit {
+		return Rule{}, fmt.Errorf("totalistic code must be between 0 and %d for radius %d, got %d", limit-1, radius, code)
+	}
+
+	next := func(neighborhood []bool) bool {
+		count := 0
+		for _, alive := range neighborhood {
+			if alive {
+				count++
+			}
+		}
+
+		return code&(1<<count) != 0
+	}
+
+	return Rule{radius, next}, nil
+}
+
+// Parse a rule: an elementary rule number like "30", or a totalistic rule
+// written as "totalistic:CODE" or "totalistic:CODE,RADIUS"
+func ParseRule(text string) (Rule, error) {
+	name, parameters, found := strings.Cut(text, ":")
+	if !found {
+		number, err := strconv.Atoi(text)
+		if err != nil {
+			return Rule{}, fmt.Errorf("rule %s must be an elementary rule number or totalistic:CODE[,RADIUS]", text)
+		}
+		return Elementary(number)
+	}
+
+	if name != "totalistic" {
+		return Rule{}, fmt.Errorf("rule %s must be an elementary rule number or totalistic:CODE[,RADIUS]", text)
+	}
+
+	values := []int{}
+	for _, field := range strings.Split(parameters, ",") {
+		value, err := strconv.Atoi(field)
+		if err != nil {
+			return Rule{}, fmt.Errorf("rule %s must be an elementary rule number or totalistic:CODE[,RADIUS]", text)
+		}
+		values = append(values, value)
+	}
+
+	switch len(values) {
+	case 1:
+		return Totalistic(values[0], 1)
+	case 2:
+		return Totalistic(values[0], values[1])
+	default:
+		return Rule{}, fmt.Errorf("rule %s must be an elementary rule number or totalistic:CODE[,RADIUS]", text)
+	}
+}
+
+// What happens past the edges of the fabric
+type Edges int
+
+const (
+	// The row wraps around, as if the fabric were a tube
+	Wrap Edges = iota
+	// Cells past the edges are always knits
+	Fixed
+)
+
+func ParseEdges(name string) (Edges, error) {
+	switch name {
+	case "wrap":
+		return Wrap, nil
+	case "fixed":
+		return Fixed, nil
+	default:
+		return Wrap, fmt.Errorf("edges must be wrap or fixed, got %s", name)
+	}
+}
+
+// Compute the next row of the automaton
+func Step(row knitting.Row, rule Rule, edges Edges) knitting.Row {
+	width := len(row)
+	neighborhood := make([]bool, 2*rule.Radius+1)
+	result := make(knitting.Row, width)
+	for i := range row {
+		for j := range neighborhood {
+			column := i + j - rule.Radius
+			switch {
+			case column >= 0 && column < width:
+				neighborhood[j] = row[column] == knitting.Purl
+			case edges == Wrap:
+				neighborhood[j] = row[((column%width)+width)%width] == knitting.Purl
+			default:
+				neighborhood[j] = false
+			}
+		}
+
+		if rule.next(neighborhood) {
+			result[i] = knitting.Purl
+		} else {
+			result[i] = knitting.Knit
+		}
+	}
+
+	return result
+}
+
+// The rows of an automaton, up to where it starts repeating
+type Evolution struct {
+	// The rows as they are worked by the knitter, starting with the seed.
+	// The set up rows come first, followed by one repeat. Every second
+	// row is worked on the wrong side.
+	Rows knitting.Fabric
+	// How many rows come before the automaton enters a cycle
+	SetupRows int
+	// How many rows the automaton takes to return to the same row
+	Cycle int
+	// How many rows the knitted repeat takes. This is Cycle, or twice
+	// Cycle if it is odd so the repeat starts on the same side of the
+	// fabric.
+	RepeatRows int
+}
+
+// Run the automaton from the seed row until it enters a cycle. The
+// automaton runs on the chart, so the seed is the bottom row of the chart
+// read from left to right, and each row is computed from the stitches
+// directly below it. The rows are then listed in stitching order.
+func Evolve(seed knitting.Row, rule Rule, edges Edges) (Evolution, error) {
+	if len(seed) == 0 {
+		return Evolution{}, errors.New("seed must not be empty")
+	}
+
+	for _, stitch := range seed {
+		if stitch != knitting.Knit && stitch != knitting.Purl {
+			return Evolution{}, errors.New("seed must only contain knits (v) and purls (-)")
+		}
+	}
+
+	// Chart rows from the bottom up
+	chart := knitting.Fabric{}
+	seen := map[string]int{}
+	row := seed
+	for len(chart) < MaxRows {
+		key := row.ToString()
+		if first, ok := seen[key]; ok {
+			cycle := len(chart) - first
+			repeat := cycle
+			if cycle%2 == 1 {
+				repeat *= 2
+				chart = append(chart, chart[first:]...)
+			}
+
+			return Evolution{toStitchingOrder(chart), first, cycle, repeat}, nil
+		}
+
+		seen[key] = len(chart)
+		chart = append(chart, row)
+		row = Step(row, rule, edges)
+	}
+
+	return Evolution{}, fmt.Errorf("the automaton does not repeat within %d rows", MaxRows)
+}
+
+// Convert chart rows listed from the bottom up to stitching order. Right
+// side rows are worked from right to left. Wrong side rows are worked from
+// left to right as seen from the front, with knits and purls swapped.
+func toStitchingOrder(chart knitting.Fabric) knitting.Fabric {
+	result := make(knitting.Fabric, len(chart))
+	for i, row := range chart {
+		if i%2 == 0 {
+			result[i] = row.Reverse()
+		} else {
+			result[i] = row.SwapKnitsAndPurls()
+		}
+	}
+
+	return result
+}
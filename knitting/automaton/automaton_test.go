@@ -0,0 +1,169 @@
+package automaton
+
+import (
+	"testing"
+
+	"github.com/ptrgags/mindless-stitchcraft/checks"
+	"github.com/ptrgags/mindless-stitchcraft/knitting"
+)
+
+func parseRow(t *testing.T, text string) knitting.Row {
+	motif, err := knitting.ParseMotif(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return knitting.Row(motif)
+}
+
+func checkRow(t *testing.T, row knitting.Row, expected string) {
+	if row.ToString() != expected {
+		t.Errorf("expected %s, got %s", expected, row.ToString())
+	}
+}
+
+func TestParseRule(t *testing.T) {
+	t.Run("invalid rules return error", func(t *testing.T) {
+		cases := []struct {
+			text     string
+			expected string
+		}{
+			{"256", "elementary rule must be between 0 and 255, got 256"},
+			{"rule30", "rule rule30 must be an elementary rule number or totalistic:CODE[,RADIUS]"},
+			{"totalistic:16", "totalistic code must be between 0 and 15 for radius 1, got 16"},
+			{"totalistic:4,4", "radius must be between 1 and 3"},
+			{"outer:4", "rule outer:4 must be an elementary rule number or totalistic:CODE[,RADIUS]"},
+		}
+
+		for _, tc := range cases {
+			t.Run(tc.text, func(t *testing.T) {
+				result, err := ParseRule(tc.text)
+
+				checks.CheckHasError(t, result, err, tc.expected)
+			})
+		}
+	})
+
+	t.Run("totalistic rule can have a larger radius", func(t *testing.T) {
+		result, err := ParseRule("totalistic:20,2")
+
+		checks.CheckHasNoError(t, result, err)
+		if result.Radius != 2 {
+			t.Errorf("expected radius 2, got %d", result.Radius)
+		}
+	})
+}
+
+func TestStep(t *testing.T) {
+	t.Run("rule 90 makes each cell the XOR of its neighbors", func(t *testing.T) {
+		rule, _ := Elementary(90)
+
+		result := Step(parseRow(t, "vvv-vvv"), rule, Wrap)
+
+		checkRow(t, result, "vv-v-vv")
+	})
+
+	t.Run("rule 30 grows unevenly", func(t *testing.T) {
+		rule, _ := Elementary(30)
+
+		result := Step(parseRow(t, "vv---vv"), rule, Wrap)
+
+		checkRow(t, result, "v--vv-v")
+	})
+
+	t.Run("wrap edges see the other side of the row", func(t *testing.T) {
+		rule, _ := Elementary(90)
+
+		result := Step(parseRow(t, "-vvv"), rule, Wrap)
+
+		checkRow(t, result, "v-v-")
+	})
+
+	t.Run("fixed edges see knits past the edge", func(t *testing.T) {
+		rule, _ := Elementary(90)
+
+		result := Step(parseRow(t, "-vvv"), rule, Fixed)
+
+		checkRow(t, result, "v-vv")
+	})
+
+	t.Run("totalistic rule counts live cells", func(t *testing.T) {
+		// Alive with exactly 2 live cells in the neighborhood
+		rule, _ := Totalistic(4, 1)
+
+		result := Step(parseRow(t, "-v-vv"), rule, Fixed)
+
+		checkRow(t, result, "v-vvv")
+	})
+}
+
+func TestEvolve(t *testing.T) {
+	t.Run("empty seed returns error", func(t *testing.T) {
+		rule, _ := Elementary(90)
+
+		result, err := Evolve(knitting.Row{}, rule, Wrap)
+
+		checks.CheckHasError(t, result, err, "seed must not be empty")
+	})
+
+	t.Run("seed with other stitches returns error", func(t *testing.T) {
+		rule, _ := Elementary(90)
+
+		result, err := Evolve(parseRow(t, "v-o"), rule, Wrap)
+
+		checks.CheckHasError(t, result, err, "seed must only contain knits (v) and purls (-)")
+	})
+
+	t.Run("finds set up rows and the cycle", func(t *testing.T) {
+		rule, _ := Elementary(110)
+
+		result, err := Evolve(parseRow(t, "vvvv-vvv"), rule, Fixed)
+
+		checks.CheckHasNoError(t, result, err)
+		if result.SetupRows != 7 || result.Cycle != 2 || result.RepeatRows != 2 {
+			t.Errorf("expected 7 set up rows and a 2 row cycle, got %d and %d", result.SetupRows, result.Cycle)
+		}
+		if len(result.Rows) != 9 {
+			t.Errorf("expected 9 rows, got %d", len(result.Rows))
+		}
+	})
+
+	t.Run("odd cycle is doubled to return to the same side", func(t *testing.T) {
+		rule, _ := Elementary(90)
+
+		result, err := Evolve(parseRow(t, "vvvv-vvvv"), rule, Wrap)
+
+		checks.CheckHasNoError(t, result, err)
+		if result.SetupRows != 1 || result.Cycle != 7 || result.RepeatRows != 14 {
+			t.Errorf("expected 1 set up row and a 7 row cycle doubled to 14, got %v", result)
+		}
+		// The same rows of the chart are worked on opposite sides
+		chart := result.Rows.HandleReverseRows()
+		checks.CheckSlicesEqual(t, chart[1:8].ToStrings(), chart[8:].ToStrings())
+	})
+
+	t.Run("rows are computed on the chart and listed in stitching order", func(t *testing.T) {
+		// Rule 30 is not symmetric, so working the rows in the wrong
+		// direction would change the pattern
+		rule, _ := Elementary(30)
+
+		result, err := Evolve(parseRow(t, "v-vvvvv"), rule, Wrap)
+
+		checks.CheckHasNoError(t, result, err)
+		chart := []string{"v------", "-vv-vv-", "---vvvv", "v-vvvvv"}
+		checks.CheckSlicesEqual(t, result.Rows.HandleReverseRows().Rotate180().ToStrings(), chart)
+
+		// Right side rows are reversed, wrong side rows are only swapped
+		expected := []string{"vvvvv-v", "vvv----", "-vv-vv-", "-vvvvvv"}
+		checks.CheckSlicesEqual(t, result.Rows.ToStrings(), expected)
+	})
+
+	t.Run("constant row repeats right away", func(t *testing.T) {
+		rule, _ := Elementary(204)
+
+		result, err := Evolve(parseRow(t, "v-v-"), rule, Wrap)
+
+		checks.CheckHasNoError(t, result, err)
+		checks.CheckSlicesEqual(t, result.Rows.ToStrings(), []string{"-v-v", "-v-v"})
+	})
+}
@@ -0,0 +1,180 @@
+package border
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ptrgags/mindless-stitchcraft/knitting"
+)
+
+type Style int
+
+const (
+	None Style = iota
+	// Knit every stitch of every row
+	Garter
+	// Alternate knits and purls in a checkerboard
+	Seed
+	// Slip the first stitch of every row and knit the last one, which
+	// makes a neat chain along the edge
+	Slipped
+)
+
+// A frame of edge stitches around the motif area of a flat fabric
+type Border struct {
+	Style Style
+	// How many border stitches are added at each side of every row
+	Stitches int
+	// How many full rows of border are added at the bottom and at the top
+	// of the fabric. This is always even so the motif area starts on the
+	// right side.
+	Rows int
+}
+
+// Parse a border description: "none", "garter:N", "seed:N" or "slip".
+// Garter and seed borders are N stitches wide at the sides, with bands of
+// 2N rows at the bottom and top. The slipped border has no bands.
+func ParseBorder(description string) (Border, error) {
+	switch description {
+	case "none":
+		return Border{}, nil
+	case "slip":
+		return Border{Style: Slipped, Stitches: 1}, nil
+	}
+
+	styleName, parameter, found := strings.Cut(description, ":")
+	var style Style
+	switch styleName {
+	case "garter":
+		style = Garter
+	case "seed":
+		style = Seed
+	default:
+		return Border{}, fmt.Errorf("unknown border %s, must be none, garter:N, seed:N or slip", description)
+	}
+
+	if !found {
+		return Border{}, fmt.Errorf("border %s must have a width, e.g. %s:3", description, styleName)
+	}
+
+	width, err := strconv.Atoi(parameter)
+	if err != nil || width < 1 {
+		return Border{}, fmt.Errorf("border width must be a positive integer in %s", description)
+	}
+
+	return Border{Style: style, Stitches: width, Rows: 2 * width}, nil
+}
+
+// Get the stitch to work at the given position of a row (counting in the
+// order the stitches are worked) so the border looks right from the front.
+func (border Border) workedStitch(row int, position int, width int) knitting.KnitStitch {
+	switch border.Style {
+	case Seed:
+		// Rows on the right side are worked from the right edge of the
+		// chart, and rows on the wrong side show the opposite stitch. Either
+		// way, the front is a checkerboard.
+		if row%2 == 0 {
+			if (row+width-1-position)%2 == 0 {
+				return knitting.Knit
+			}
+			return knitting.Purl
+		}
+
+		if (row+position)%2 == 1 {
+			return knitting.Knit
+		}
+		return knitting.Purl
+	case Slipped:
+		if position == 0 {
+			return knitting.SlipWyif
+		}
+		return knitting.Knit
+	default:
+		return knitting.Knit
+	}
+}
+
+// Frame a flat fabric with the border. Both the input and the output list
+// the rows in stitching order. Border stitches are added on top of the
+// width of the motif area.
+func (border Border) Apply(stitchingOrder knitting.Fabric) knitting.Fabric {
+	if border.Style == None || len(stitchingOrder) == 0 {
+		return stitchingOrder
+	}
+
+	width := len(stitchingOrder[0]) + 2*border.Stitches
+	result := make(knitting.Fabric, 0, len(stitchingOrder)+2*border.Rows)
+
+	band := func(row int) knitting.Row {
+		stitches := make(knitting.Row, width)
+		for i := range stitches {
+			stitches[i] = border.workedStitch(row, i, width)
+		}
+		return stitches
+	}
+
+	for i := 0; i < border.Rows; i++ {
+		result = append(result, band(len(result)))
+	}
+
+	for _, bodyRow := range stitchingOrder {
+		row := len(result)
+		stitches := make(knitting.Row, 0, width)
+		for i := 0; i < border.Stitches; i++ {
+			stitches = append(stitches, border.workedStitch(row, i, width))
+		}
+		stitches = append(stitches, bodyRow...)
+		for i := width - border.Stitches; i < width; i++ {
+			stitches = append(stitches, border.workedStitch(row, i, width))
+		}
+		result = append(result, stitches)
+	}
+
+	for i := 0; i < border.Rows; i++ {
+		result = append(result, band(len(result)))
+	}
+
+	return result
+}
+
+// Mark where the border is in a chart of a framed fabric: a '|' between
+// the side borders and the motif area, and a line of '=' between the
+// bands and the motif area.
+func (border Border) MarkChart(chart []string) []string {
+	if border.Style == None {
+		return chart
+	}
+
+	marked := make([]string, len(chart))
+	for i, row := range chart {
+		runes := []rune(row)
+		if len(runes) < 2*border.Stitches {
+			marked[i] = row
+			continue
+		}
+
+		left := string(runes[:border.Stitches])
+		middle := string(runes[border.Stitches : len(runes)-border.Stitches])
+		right := string(runes[len(runes)-border.Stitches:])
+		marked[i] = left + "|" + middle + "|" + right
+	}
+
+	if border.Rows == 0 || len(chart) < 2*border.Rows {
+		return marked
+	}
+
+	width := len([]rune(chart[0]))
+	separator := strings.Repeat("=", border.Stitches) + "+" +
+		strings.Repeat("=", width-2*border.Stitches) + "+" +
+		strings.Repeat("=", border.Stitches)
+
+	result := make([]string, 0, len(marked)+2)
+	result = append(result, marked[:border.Rows]...)
+	result = append(result, separator)
+	result = append(result, marked[border.Rows:len(marked)-border.Rows]...)
+	result = append(result, separator)
+	result = append(result, marked[len(marked)-border.Rows:]...)
+
+	return result
+}
@@ -0,0 +1,166 @@
+package border
+
+import (
+	"testing"
+
+	"github.com/ptrgags/mindless-stitchcraft/checks"
+	"github.com/ptrgags/mindless-stitchcraft/knitting"
+)
+
+func parseFabric(t *testing.T, rows ...string) knitting.Fabric {
+	fabric := make(knitting.Fabric, len(rows))
+	for i, row := range rows {
+		motif, err := knitting.ParseMotif(row)
+		if err != nil {
+			t.Fatalf("invalid row %s: %v", row, err)
+		}
+		fabric[i] = knitting.Row(motif)
+	}
+
+	return fabric
+}
+
+func TestParseBorder(t *testing.T) {
+	t.Run("parses border styles", func(t *testing.T) {
+		cases := []struct {
+			description string
+			expected    Border
+		}{
+			{"none", Border{}},
+			{"garter:3", Border{Garter, 3, 6}},
+			{"seed:2", Border{Seed, 2, 4}},
+			{"slip", Border{Slipped, 1, 0}},
+		}
+
+		for _, tc := range cases {
+			t.Run(tc.description, func(t *testing.T) {
+				result, err := ParseBorder(tc.description)
+
+				checks.CheckHasNoError(t, result, err)
+				if result != tc.expected {
+					t.Errorf("expected %v, got %v", tc.expected, result)
+				}
+			})
+		}
+	})
+
+	t.Run("unknown style returns error", func(t *testing.T) {
+		result, err := ParseBorder("ribbing:2")
+
+		checks.CheckHasError(t, result, err, "unknown border ribbing:2, must be none, garter:N, seed:N or slip")
+	})
+
+	t.Run("missing width returns error", func(t *testing.T) {
+		result, err := ParseBorder("garter")
+
+		checks.CheckHasError(t, result, err, "border garter must have a width, e.g. garter:3")
+	})
+
+	t.Run("invalid width returns error", func(t *testing.T) {
+		result, err := ParseBorder("seed:0")
+
+		checks.CheckHasError(t, result, err, "border width must be a positive integer in seed:0")
+	})
+}
+
+func TestApply(t *testing.T) {
+	t.Run("no border returns the fabric unchanged", func(t *testing.T) {
+		fabric := parseFabric(t, "v--", "-vv")
+
+		result := Border{}.Apply(fabric)
+
+		checks.CheckSlicesEqual(t, result.ToStrings(), []string{"v--", "-vv"})
+	})
+
+	t.Run("garter border knits every border stitch", func(t *testing.T) {
+		fabric := parseFabric(t, "v--", "-vv")
+
+		result := Border{Garter, 1, 2}.Apply(fabric)
+
+		expected := []string{
+			"vvvvv",
+			"vvvvv",
+			"vv--v",
+			"v-vvv",
+			"vvvvv",
+			"vvvvv",
+		}
+		checks.CheckSlicesEqual(t, result.ToStrings(), expected)
+	})
+
+	t.Run("seed border is a checkerboard from the front", func(t *testing.T) {
+		fabric := parseFabric(t, "vv", "vv")
+
+		result := Border{Seed, 1, 2}.Apply(fabric)
+		chart := result.HandleReverseRows().Rotate180().ToStrings()
+
+		expected := []string{
+			"-v-v",
+			"v-v-",
+			"---v",
+			"vvv-",
+			"-v-v",
+			"v-v-",
+		}
+		checks.CheckSlicesEqual(t, chart, expected)
+	})
+
+	t.Run("slipped border slips the first stitch and knits the last", func(t *testing.T) {
+		fabric := parseFabric(t, "--", "--")
+
+		result := Border{Slipped, 1, 0}.Apply(fabric)
+
+		checks.CheckSlicesEqual(t, result.ToStrings(), []string{"f--v", "f--v"})
+	})
+
+	t.Run("border does not count toward the motif width", func(t *testing.T) {
+		fabric := parseFabric(t, "v-v-v", "-v-v-")
+
+		result := Border{Garter, 2, 4}.Apply(fabric)
+
+		checks.CheckStringGridShape(t, result.ToStrings(), 9, 10)
+	})
+}
+
+func TestMarkChart(t *testing.T) {
+	t.Run("no border leaves chart unchanged", func(t *testing.T) {
+		chart := []string{"v--", "-vv"}
+
+		result := Border{}.MarkChart(chart)
+
+		checks.CheckSlicesEqual(t, result, chart)
+	})
+
+	t.Run("marks side borders and bands", func(t *testing.T) {
+		chart := []string{
+			"vvvv",
+			"----",
+			"v--v",
+			"-vv-",
+			"vvvv",
+			"----",
+		}
+
+		result := Border{Garter, 1, 2}.MarkChart(chart)
+
+		expected := []string{
+			"v|vv|v",
+			"-|--|-",
+			"=+==+=",
+			"v|--|v",
+			"-|vv|-",
+			"=+==+=",
+			"v|vv|v",
+			"-|--|-",
+		}
+		checks.CheckSlicesEqual(t, result, expected)
+	})
+
+	t.Run("side borders without bands", func(t *testing.T) {
+		chart := []string{"s--v", "v--f"}
+
+		result := Border{Slipped, 1, 0}.MarkChart(chart)
+
+		checks.CheckSlicesEqual(t, result, []string{"s|--|v", "v|--|f"})
+	})
+}
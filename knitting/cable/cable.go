@@ -0,0 +1,284 @@
+package cable
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/ptrgags/mindless-stitchcraft/stitchmath"
+)
+
+// Strands are labeled with one letter each, so panels are limited to this
+// many stitches
+const strandLabels = "abcdefghijklmnopqrstuvwxyz"
+
+type Kind int
+
+const (
+	// Cable with half the stitches held in front, e.g. C4F. The cable
+	// leans left.
+	CrossFront Kind = iota
+	// Cable with half the stitches held in back, e.g. C4B. The cable
+	// leans right.
+	CrossBack
+	// Twist where the knit stitches are held in front and travel left
+	// over the background, e.g. T3L
+	TwistLeft
+	// Twist where the background stitches are held in back, so the knit
+	// stitches travel right over them, e.g. T3R
+	TwistRight
+)
+
+// A cable crossing within a row
+type Cross struct {
+	Kind Kind
+	// How many stitches the crossing works, e.g. 4 for C4F
+	Stitches int
+	// Rightmost column of the crossing, counting from 1 at the right edge
+	// of the chart
+	Column int
+}
+
+func (cross Cross) String() string {
+	var name string
+	switch cross.Kind {
+	case CrossFront:
+		name = fmt.Sprintf("C%dF", cross.Stitches)
+	case CrossBack:
+		name = fmt.Sprintf("C%dB", cross.Stitches)
+	case TwistLeft:
+		name = fmt.Sprintf("T%dL", cross.Stitches)
+	default:
+		name = fmt.Sprintf("T%dR", cross.Stitches)
+	}
+
+	return fmt.Sprintf("%s@%d", name, cross.Column)
+}
+
+// How many stitches are slipped to the cable needle. These are the first
+// stitches of the crossing to be reached, and they end up on the left.
+func (cross Cross) Held() int {
+	switch cross.Kind {
+	case TwistLeft:
+		return (cross.Stitches + 1) / 2
+	default:
+		// Cables hold half the stitches, and right twists hold the
+		// background stitches, which is the smaller half
+		return cross.Stitches / 2
+	}
+}
+
+// True if the held stitches pass in front of the others
+func (cross Cross) HeldInFront() bool {
+	return cross.Kind == CrossFront || cross.Kind == TwistLeft
+}
+
+// The columns (counting from 0 at the right edge) covered by the crossing
+func (cross Cross) end() int {
+	return cross.Column - 1 + cross.Stitches
+}
+
+// Matches e.g. "C4F@3" or "t3l@10"
+var crossPattern = regexp.MustCompile(`^([CT])(\d+)([FBLR])@(\d+)$`)
+
+// Parse a crossing like "C4F@3": C4F, C6B, T3L or T3R followed by the
+// column of its rightmost stitch
+func ParseCross(text string) (Cross, error) {
+	matches := crossPattern.FindStringSubmatch(strings.ToUpper(text))
+	if matches == nil {
+		return Cross{}, fmt.Errorf("crossing %s must look like C4F@COLUMN, C4B@COLUMN, T3L@COLUMN or T3R@COLUMN", text)
+	}
+
+	stitches, err := strconv.Atoi(matches[2])
+	if err != nil {
+		return Cross{}, fmt.Errorf("crossing %s has too many stitches", text)
+	}
+
+	column, err := strconv.Atoi(matches[4])
+	if err != nil {
+		return Cross{}, fmt.Errorf("crossing %s has a column that is too large", text)
+	}
+
+	var kind Kind
+	switch matches[1] + matches[3] {
+	case "CF":
+		kind = CrossFront
+	case "CB":
+		kind = CrossBack
+	case "TL":
+		kind = TwistLeft
+	case "TR":
+		kind = TwistRight
+	default:
+		return Cross{}, fmt.Errorf("crossing %s must be a cable (C, front or back) or a twist (T, left or right)", text)
+	}
+
+	if stitches < 2 {
+		return Cross{}, fmt.Errorf("crossing %s must work at least 2 stitches", text)
+	}
+
+	if column < 1 {
+		return Cross{}, fmt.Errorf("crossing %s must start at column 1 or more", text)
+	}
+
+	return Cross{kind, stitches, column}, nil
+}
+
+// Parse the crossings of a row, separated by spaces or commas. A row
+// without crossings is written as "-".
+func ParseRow(text string) ([]Cross, error) {
+	fields := strings.FieldsFunc(text, func(r rune) bool {
+		return unicode.IsSpace(r) || r == ','
+	})
+
+	result := []Cross{}
+	for _, field := range fields {
+		if field == "-" {
+			continue
+		}
+
+		cross, err := ParseCross(field)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, cross)
+	}
+
+	return result, nil
+}
+
+// Compute where each stitch of a row ends up after the crossings. Stitches
+// are numbered by column from the right edge, starting from 0, which is
+// also the order they are worked in on the right side.
+//
+// Within a crossing, the held stitches are worked last, so they move left
+// past the other stitches.
+func RowPermutation(width int, crosses []Cross) (stitchmath.Permutation, error) {
+	covered := make([]*Cross, width)
+	values := stitchmath.MakeIdentity(width).GetValues()
+	for i := range crosses {
+		cross := &crosses[i]
+		// Compare without computing the end, which could overflow
+		if cross.Column-1 > width-cross.Stitches {
+			return stitchmath.Permutation{}, fmt.Errorf("%s goes past the edge of the %d stitch panel", cross, width)
+		}
+
+		start := cross.Column - 1
+		for column := start; column < cross.end(); column++ {
+			if covered[column] != nil {
+				return stitchmath.Permutation{}, fmt.Errorf("%s overlaps %s", covered[column], cross)
+			}
+			covered[column] = cross
+		}
+
+		held := cross.Held()
+		for offset := 0; offset < cross.Stitches; offset++ {
+			if offset < held {
+				values[start+offset] = uint(start + offset + cross.Stitches - held)
+			} else {
+				values[start+offset] = uint(start + offset - held)
+			}
+		}
+	}
+
+	return stitchmath.MakePermutation(values)
+}
+
+// A cable panel, with the crossings of each row
+type Pattern struct {
+	Width int
+	Rows  [][]Cross
+	// The permutation of each row
+	permutations []stitchmath.Permutation
+}
+
+// Make a cable panel width stitches wide. Rows are listed from the first
+// row worked to the last.
+func New(width int, rows [][]Cross) (Pattern, error) {
+	if width < 1 || width > len(strandLabels) {
+		return Pattern{}, fmt.Errorf("width must be between 1 and %d", len(strandLabels))
+	}
+
+	if len(rows) == 0 {
+		return Pattern{}, errors.New("rows must be non-empty")
+	}
+
+	permutations := make([]stitchmath.Permutation, len(rows))
+	for i, row := range rows {
+		permutation, err := RowPermutation(width, row)
+		if err != nil {
+			return Pattern{}, fmt.Errorf("row %d: %w", i+1, err)
+		}
+		permutations[i] = permutation
+	}
+
+	return Pattern{width, rows, permutations}, nil
+}
+
+// The permutation of one repeat of the rows, i.e. which column each
+// stitch ends up in after working every row once.
+func (pattern Pattern) Repeat() stitchmath.Permutation {
+	product := stitchmath.MakeIdentity(pattern.Width)
+	for _, permutation := range pattern.permutations {
+		// The permutations all have the same length, so this can't fail
+		product, _ = stitchmath.Compose(permutation, product)
+	}
+
+	return product
+}
+
+// How many repeats of the rows it takes for every stitch to return to the
+// column it started in
+func (pattern Pattern) RepeatsUntilAligned() int {
+	return int(pattern.Repeat().Order())
+}
+
+// Chart the paths of the stitches for the given number of repeats. Each
+// stitch is labeled with a letter from the column it is cast on in (a is
+// the rightmost column), and each row shows which stitch is in each
+// column after the row is worked. Stitches that pass in front in a
+// crossing are shown in uppercase. The first row is at the bottom.
+func (pattern Pattern) Paths(repeats int) []string {
+	// Which stitch is in each column, counting from the right
+	strands := []rune(strandLabels[:pattern.Width])
+
+	rows := len(pattern.Rows) * repeats
+	result := make([]string, rows)
+	for r := 0; r < rows; r++ {
+		permutation := pattern.permutations[r%len(pattern.Rows)]
+		moved := make([]rune, pattern.Width)
+		for column, strand := range strands {
+			moved[permutation.Apply(uint(column))] = strand
+		}
+
+		// The held stitches of crossings held in front, after crossing
+		display := make([]rune, pattern.Width)
+		copy(display, moved)
+		for _, cross := range pattern.Rows[r%len(pattern.Rows)] {
+			held := cross.Held()
+			start := cross.Column - 1 + cross.Stitches - held
+			end := cross.end()
+			if !cross.HeldInFront() {
+				start = cross.Column - 1
+				end = cross.end() - held
+			}
+
+			for column := start; column < end; column++ {
+				display[column] = unicode.ToUpper(display[column])
+			}
+		}
+
+		// Charts list columns from left to right
+		line := make([]rune, pattern.Width)
+		for column, strand := range display {
+			line[pattern.Width-1-column] = strand
+		}
+		result[rows-1-r] = string(line)
+		strands = moved
+	}
+
+	return result
+}
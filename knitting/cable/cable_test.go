@@ -0,0 +1,184 @@
+package cable
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ptrgags/mindless-stitchcraft/checks"
+)
+
+func TestParseCross(t *testing.T) {
+	t.Run("parses cables and twists", func(t *testing.T) {
+		cases := []struct {
+			text     string
+			expected Cross
+		}{
+			{"C4F@1", Cross{CrossFront, 4, 1}},
+			{"c6b@3", Cross{CrossBack, 6, 3}},
+			{"T3L@10", Cross{TwistLeft, 3, 10}},
+			{"T3R@2", Cross{TwistRight, 3, 2}},
+		}
+
+		for _, tc := range cases {
+			result, err := ParseCross(tc.text)
+
+			checks.CheckHasNoError(t, result, err)
+			if result != tc.expected {
+				t.Errorf("expected %v, got %v", tc.expected, result)
+			}
+		}
+	})
+
+	t.Run("missing column returns error", func(t *testing.T) {
+		result, err := ParseCross("C4F")
+
+		checks.CheckHasError(t, result, err, "crossing C4F must look like C4F@COLUMN, C4B@COLUMN, T3L@COLUMN or T3R@COLUMN")
+	})
+
+	t.Run("mismatched direction returns error", func(t *testing.T) {
+		result, err := ParseCross("C4L@1")
+
+		checks.CheckHasError(t, result, err, "crossing C4L@1 must be a cable (C, front or back) or a twist (T, left or right)")
+	})
+
+	t.Run("single stitch returns error", func(t *testing.T) {
+		result, err := ParseCross("C1F@1")
+
+		checks.CheckHasError(t, result, err, "crossing C1F@1 must work at least 2 stitches")
+	})
+
+	t.Run("numbers that are too large return error", func(t *testing.T) {
+		cases := []struct {
+			text     string
+			expected string
+		}{
+			{"C99999999999999999999F@1", "crossing C99999999999999999999F@1 has too many stitches"},
+			{"C4F@99999999999999999999", "crossing C4F@99999999999999999999 has a column that is too large"},
+		}
+
+		for _, tc := range cases {
+			t.Run(tc.text, func(t *testing.T) {
+				result, err := ParseCross(tc.text)
+
+				checks.CheckHasError(t, result, err, tc.expected)
+			})
+		}
+	})
+}
+
+func TestParseRow(t *testing.T) {
+	t.Run("plain row has no crossings", func(t *testing.T) {
+		result, err := ParseRow("-")
+
+		checks.CheckHasNoError(t, result, err)
+		checks.CheckSliceEmpty(t, result)
+	})
+
+	t.Run("parses several crossings", func(t *testing.T) {
+		result, err := ParseRow("C4F@1, T3R@6")
+
+		checks.CheckHasNoError(t, result, err)
+		checks.CheckSlicesEqual(t, result, []Cross{{CrossFront, 4, 1}, {TwistRight, 3, 6}})
+	})
+}
+
+func TestHeld(t *testing.T) {
+	cases := []struct {
+		cross    Cross
+		expected int
+	}{
+		{Cross{CrossFront, 4, 1}, 2},
+		{Cross{CrossBack, 6, 1}, 3},
+		{Cross{TwistLeft, 3, 1}, 2},
+		{Cross{TwistRight, 3, 1}, 1},
+	}
+
+	for _, tc := range cases {
+		result := tc.cross.Held()
+
+		if result != tc.expected {
+			t.Errorf("expected %s to hold %d stitches, got %d", tc.cross, tc.expected, result)
+		}
+	}
+}
+
+func TestRowPermutation(t *testing.T) {
+	t.Run("held stitches move left past the others", func(t *testing.T) {
+		result, err := RowPermutation(6, []Cross{{TwistLeft, 3, 2}})
+
+		checks.CheckHasNoError(t, result, err)
+		checks.CheckSlicesEqual(t, result.GetValues(), []uint{0, 2, 3, 1, 4, 5})
+	})
+
+	t.Run("crossing past the edge returns error", func(t *testing.T) {
+		cases := []struct {
+			cross    Cross
+			expected string
+		}{
+			{Cross{CrossFront, 4, 2}, "C4F@2 goes past the edge of the 4 stitch panel"},
+			{Cross{CrossFront, 2, math.MaxInt}, "C2F@9223372036854775807 goes past the edge of the 4 stitch panel"},
+			{Cross{CrossFront, math.MaxInt, 2}, "C9223372036854775807F@2 goes past the edge of the 4 stitch panel"},
+		}
+
+		for _, tc := range cases {
+			t.Run(tc.expected, func(t *testing.T) {
+				result, err := RowPermutation(4, []Cross{tc.cross})
+
+				checks.CheckHasError(t, result, err, tc.expected)
+			})
+		}
+	})
+
+	t.Run("overlapping crossings return error", func(t *testing.T) {
+		result, err := RowPermutation(8, []Cross{{CrossFront, 4, 1}, {TwistRight, 3, 4}})
+
+		checks.CheckHasError(t, result, err, "C4F@1 overlaps T3R@4")
+	})
+}
+
+func TestNew(t *testing.T) {
+	t.Run("errors mention the row", func(t *testing.T) {
+		result, err := New(4, [][]Cross{{}, {{CrossFront, 4, 2}}})
+
+		checks.CheckHasError(t, result, err, "row 2: C4F@2 goes past the edge of the 4 stitch panel")
+	})
+
+	t.Run("too wide panel returns error", func(t *testing.T) {
+		result, err := New(27, [][]Cross{{}})
+
+		checks.CheckHasError(t, result, err, "width must be between 1 and 26")
+	})
+}
+
+func TestRepeat(t *testing.T) {
+	t.Run("rope cable returns every repeat", func(t *testing.T) {
+		pattern, _ := New(4, [][]Cross{{{CrossFront, 4, 1}}, {}, {{CrossFront, 4, 1}}, {}})
+
+		if pattern.RepeatsUntilAligned() != 1 {
+			t.Errorf("expected 1 repeat, got %d", pattern.RepeatsUntilAligned())
+		}
+	})
+
+	t.Run("traveling stitch takes several repeats", func(t *testing.T) {
+		pattern, _ := New(4, [][]Cross{{{TwistLeft, 2, 1}}, {{TwistLeft, 2, 3}}})
+
+		checks.CheckSlicesEqual(t, pattern.Repeat().GetValues(), []uint{1, 0, 3, 2})
+		if pattern.RepeatsUntilAligned() != 2 {
+			t.Errorf("expected 2 repeats, got %d", pattern.RepeatsUntilAligned())
+		}
+	})
+}
+
+func TestPaths(t *testing.T) {
+	pattern, _ := New(4, [][]Cross{{{CrossFront, 4, 1}}, {}})
+
+	result := pattern.Paths(2)
+
+	expected := []string{
+		"dcba",
+		"DCba",
+		"badc",
+		"BAdc",
+	}
+	checks.CheckSlicesEqual(t, result, expected)
+}
@@ -0,0 +1,48 @@
+package knitting
+
+import "strings"
+
+// ANSI background colors for shading colored charts
+var palette = []string{
+	"\x1b[41m", // red
+	"\x1b[47m", // white
+	"\x1b[44m", // blue
+	"\x1b[43m", // yellow
+	"\x1b[42m", // green
+	"\x1b[45m", // magenta
+	"\x1b[46m", // cyan
+	"\x1b[40m", // black
+}
+
+const resetColor = "\x1b[0m"
+
+// Render a chart of color letters as lines of text. If ansi is true, each
+// stitch is also shaded with a terminal background color. Shades are
+// assigned to the colors in the order they are listed in colors, skipping
+// duplicates, and cycle if there are more colors than shades.
+func RenderColors(chart [][]rune, colors []rune, ansi bool) []string {
+	shades := map[rune]string{}
+	for _, color := range colors {
+		if _, ok := shades[color]; !ok {
+			shades[color] = palette[len(shades)%len(palette)]
+		}
+	}
+
+	result := make([]string, len(chart))
+	for i, row := range chart {
+		if !ansi {
+			result[i] = string(row)
+			continue
+		}
+
+		var builder strings.Builder
+		for _, color := range row {
+			builder.WriteString(shades[color])
+			builder.WriteRune(color)
+		}
+		builder.WriteString(resetColor)
+		result[i] = builder.String()
+	}
+
+	return result
+}
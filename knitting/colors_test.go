@@ -0,0 +1,27 @@
+package knitting
+
+import (
+	"testing"
+
+	"github.com/ptrgags/mindless-stitchcraft/checks"
+)
+
+func TestRenderColors(t *testing.T) {
+	chart := [][]rune{[]rune("AB"), []rune("BA")}
+
+	t.Run("plain text uses the color letters", func(t *testing.T) {
+		result := RenderColors(chart, []rune("AB"), false)
+
+		checks.CheckSlicesEqual(t, result, []string{"AB", "BA"})
+	})
+
+	t.Run("ansi output shades colors in order", func(t *testing.T) {
+		result := RenderColors(chart, []rune("BAB"), true)
+
+		expected := []string{
+			"\x1b[47mA\x1b[41mB\x1b[0m",
+			"\x1b[41mB\x1b[47mA\x1b[0m",
+		}
+		checks.CheckSlicesEqual(t, result, expected)
+	})
+}
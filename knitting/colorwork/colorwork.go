@@ -0,0 +1,287 @@
+package colorwork
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/ptrgags/mindless-stitchcraft/knitting"
+	"github.com/ptrgags/mindless-stitchcraft/knitting/phase"
+)
+
+// A knit stitch worked in one of the colors of stranded colorwork. Each
+// color is written as a single letter or digit, e.g. 'R' for red.
+type ColorStitch rune
+
+type Motif []ColorStitch
+
+func ParseMotif(motif string) (Motif, error) {
+	if utf8.RuneCountInString(motif) == 0 {
+		return Motif{}, errors.New("motif must not be empty")
+	}
+
+	result := make(Motif, 0, len(motif))
+	for _, r := range motif {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			return Motif{}, fmt.Errorf("color %c must be a letter or digit", r)
+		}
+		result = append(result, ColorStitch(r))
+	}
+
+	return result, nil
+}
+
+// The colors used in the motifs, in the order they first appear
+func Colors(motifs []Motif) []rune {
+	result := []rune{}
+	for _, motif := range motifs {
+		for _, stitch := range motif {
+			if !slices.Contains(result, rune(stitch)) {
+				result = append(result, rune(stitch))
+			}
+		}
+	}
+
+	return result
+}
+
+func checkMotifs(motifs []Motif) error {
+	if len(motifs) < 1 {
+		return errors.New("motifs must be non-empty")
+	}
+
+	if len(Colors(motifs)) < 2 {
+		return errors.New("colorwork needs at least two colors")
+	}
+
+	return nil
+}
+
+// A colorwork fabric. Every stitch is a knit stitch, only the colors
+// change.
+type Fabric struct {
+	// Rows in stitching order, each listed in the order the stitches are
+	// worked
+	Rows [][]ColorStitch
+	// True if the fabric is knit in the round, so the work is never turned
+	Round bool
+}
+
+// The chart of the right side of the fabric with the first row at the
+// bottom, like the knitting charts. Colors look the same from both sides,
+// so wrong side rows are only reversed, not swapped.
+func (fabric Fabric) Chart() [][]rune {
+	worked := fabric.Rows
+	if !fabric.Round {
+		worked = knitting.ReverseAlternateRows(worked)
+	}
+
+	// Stitches are worked right to left
+	chart := make([][]rune, len(worked))
+	for r, row := range worked {
+		reversed := make([]rune, len(row))
+		for i, stitch := range row {
+			reversed[len(row)-1-i] = rune(stitch)
+		}
+		chart[len(worked)-1-r] = reversed
+	}
+
+	return chart
+}
+
+// Motifs of all knit stitches with the same lengths as the color motifs,
+// so the phase engine can decide where each row starts
+func proxyMotifs(motifs []Motif) []knitting.Motif {
+	result := make([]knitting.Motif, len(motifs))
+	for i, motif := range motifs {
+		result[i] = make(knitting.Motif, len(motif))
+	}
+
+	return result
+}
+
+func generateFlat(width int, motifs []Motif, rule phase.Rule) (Fabric, error) {
+	if err := checkMotifs(motifs); err != nil {
+		return Fabric{}, err
+	}
+
+	_, phases, err := phase.Generate(width, proxyMotifs(motifs), rule)
+	if err != nil {
+		return Fabric{}, err
+	}
+
+	rows := make([][]ColorStitch, len(phases))
+	for r, rowPhase := range phases {
+		motif := motifs[rowPhase.Motif]
+		rows[r] = make([]ColorStitch, width)
+		for i := range rows[r] {
+			rows[r][i] = motif[rowPhase.Index(len(motif), i)]
+		}
+	}
+
+	return Fabric{Rows: rows}, nil
+}
+
+// Repeat the motifs continuously across the rows of flat fabric, like
+// knit-zigzag. Multiple motifs are read one after the other.
+func GenerateZigzag(width int, motifs []Motif) (Fabric, error) {
+	return generateFlat(width, motifs, phase.Continue)
+}
+
+// Start each row of flat fabric at the beginning of the next motif, like
+// knit-sync
+func GenerateSync(width int, motifs []Motif) (Fabric, error) {
+	return generateFlat(width, motifs, phase.Restart)
+}
+
+// Repeat the motif continuously around a tube, like knit-round. If the
+// motif does not evenly divide the circumference, the colors spiral.
+func GenerateRound(circumference int, motif Motif) (Fabric, error) {
+	if circumference < 1 {
+		return Fabric{}, errors.New("circumference must be a positive integer")
+	}
+
+	if err := checkMotifs([]Motif{motif}); err != nil {
+		return Fabric{}, err
+	}
+
+	rows := [][]ColorStitch{}
+	start := 0
+	for {
+		row := make([]ColorStitch, circumference)
+		for i := range row {
+			row[i] = motif[(start+i)%len(motif)]
+		}
+		rows = append(rows, row)
+
+		start = (start + circumference) % len(motif)
+		if start == 0 {
+			break
+		}
+	}
+
+	return Fabric{Rows: rows, Round: true}, nil
+}
+
+// A strand of yarn carried across the wrong side of the fabric while
+// other colors are knit
+type Float struct {
+	// Row where the float starts, counting from 1 at the bottom of the
+	// chart
+	Row int
+	// Column of the first stitch the float passes behind, counting from
+	// 1 at the right edge of the chart. The float continues in the
+	// direction the row is worked.
+	Start int
+	// How many stitches the float passes behind
+	Length int
+	Color  rune
+}
+
+// Chart column of the stitch worked at the given offset into a row
+func (fabric Fabric) column(row int, offset int) int {
+	width := len(fabric.Rows[row])
+	if !fabric.Round && row%2 == 1 {
+		return width - offset
+	}
+
+	return offset + 1
+}
+
+type position struct {
+	row    int
+	offset int
+}
+
+// Find every float longer than maxFloat stitches.
+//
+// In flat work, every yarn is carried to the edge at the end of a row so
+// it is ready for the next row, and yarns that are not used in a row are
+// carried up the edge. In circular work, floats continue from one round
+// to the next, including from the last round of the pattern back to the
+// first.
+func (fabric Fabric) Floats(maxFloat int) []Float {
+	result := []Float{}
+	for _, color := range fabric.colors() {
+		if fabric.Round {
+			// Every stitch in the order it is worked
+			stitches := []position{}
+			for r, row := range fabric.Rows {
+				for i := range row {
+					stitches = append(stitches, position{r, i})
+				}
+			}
+			result = fabric.findFloats(result, stitches, color, maxFloat, true)
+			continue
+		}
+
+		for r, row := range fabric.Rows {
+			stitches := make([]position, len(row))
+			for i := range row {
+				stitches[i] = position{r, i}
+			}
+			result = fabric.findFloats(result, stitches, color, maxFloat, false)
+		}
+	}
+
+	slices.SortStableFunc(result, func(a Float, b Float) int {
+		if a.Row != b.Row {
+			return a.Row - b.Row
+		}
+		return a.Start - b.Start
+	})
+
+	return result
+}
+
+// Append the floats of one color along a sequence of stitches. If cyclic,
+// the sequence wraps around to the beginning, otherwise the yarn starts
+// and ends at the edges.
+func (fabric Fabric) findFloats(result []Float, stitches []position, color rune, maxFloat int, cyclic bool) []Float {
+	uses := []int{}
+	for i, p := range stitches {
+		if rune(fabric.Rows[p.row][p.offset]) == color {
+			uses = append(uses, i)
+		}
+	}
+
+	if len(uses) == 0 {
+		return result
+	}
+
+	if cyclic {
+		// Wrap around to the first use in the next repeat
+		uses = append(uses, uses[0]+len(stitches))
+	} else {
+		uses = append([]int{-1}, uses...)
+		uses = append(uses, len(stitches))
+	}
+
+	for i := 1; i < len(uses); i++ {
+		length := uses[i] - uses[i-1] - 1
+		if length <= maxFloat {
+			continue
+		}
+
+		start := stitches[(uses[i-1]+1)%len(stitches)]
+		result = append(result, Float{
+			Row:    start.row + 1,
+			Start:  fabric.column(start.row, start.offset),
+			Length: length,
+			Color:  color,
+		})
+	}
+
+	return result
+}
+
+func (fabric Fabric) colors() []rune {
+	rows := make([]Motif, len(fabric.Rows))
+	for i, row := range fabric.Rows {
+		rows[i] = Motif(row)
+	}
+
+	return Colors(rows)
+}
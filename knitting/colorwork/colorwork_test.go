@@ -0,0 +1,168 @@
+package colorwork
+
+import (
+	"testing"
+
+	"github.com/ptrgags/mindless-stitchcraft/checks"
+)
+
+func parseRows(rows ...string) [][]ColorStitch {
+	result := make([][]ColorStitch, len(rows))
+	for i, row := range rows {
+		result[i] = []ColorStitch(row)
+	}
+
+	return result
+}
+
+func chartStrings(chart [][]rune) []string {
+	result := make([]string, len(chart))
+	for i, row := range chart {
+		result[i] = string(row)
+	}
+
+	return result
+}
+
+func TestParseMotif(t *testing.T) {
+	t.Run("empty motif returns error", func(t *testing.T) {
+		result, err := ParseMotif("")
+
+		checks.CheckHasError(t, result, err, "motif must not be empty")
+	})
+
+	t.Run("symbols other than letters and digits return error", func(t *testing.T) {
+		result, err := ParseMotif("RW-")
+
+		checks.CheckHasError(t, result, err, "color - must be a letter or digit")
+	})
+
+	t.Run("parses letters and digits", func(t *testing.T) {
+		result, err := ParseMotif("R1W")
+
+		checks.CheckHasNoError(t, result, err)
+		checks.CheckSlicesEqual(t, result, Motif{'R', '1', 'W'})
+	})
+}
+
+func TestColors(t *testing.T) {
+	result := Colors([]Motif{Motif("RWR"), Motif("BW")})
+
+	checks.CheckSlicesEqual(t, result, []rune("RWB"))
+}
+
+func TestGenerateZigzag(t *testing.T) {
+	t.Run("single color returns error", func(t *testing.T) {
+		result, err := GenerateZigzag(4, []Motif{Motif("RR")})
+
+		checks.CheckHasError(t, result, err, "colorwork needs at least two colors")
+	})
+
+	t.Run("continues the motif from row to row", func(t *testing.T) {
+		result, err := GenerateZigzag(4, []Motif{Motif("RRW")})
+
+		checks.CheckHasNoError(t, result, err)
+		expected := parseRows("RRWR", "RWRR", "WRRW", "RRWR", "RWRR", "WRRW")
+		checks.CheckNestedSlicesEqual(t, result.Rows, expected)
+	})
+
+	t.Run("chart reverses wrong side rows without swapping colors", func(t *testing.T) {
+		result, err := GenerateZigzag(4, []Motif{Motif("RRW")})
+
+		checks.CheckHasNoError(t, result, err)
+		expected := []string{
+			"WRRW",
+			"RRWR",
+			"RRWR",
+			"WRRW",
+			"RWRR",
+			"RWRR",
+		}
+		checks.CheckSlicesEqual(t, chartStrings(result.Chart()), expected)
+	})
+}
+
+func TestGenerateSync(t *testing.T) {
+	t.Run("starts each row at the beginning of the next motif", func(t *testing.T) {
+		result, err := GenerateSync(3, []Motif{Motif("RW"), Motif("B")})
+
+		checks.CheckHasNoError(t, result, err)
+		expected := parseRows("RWR", "BBB")
+		checks.CheckNestedSlicesEqual(t, result.Rows, expected)
+	})
+}
+
+func TestGenerateRound(t *testing.T) {
+	t.Run("invalid circumference returns error", func(t *testing.T) {
+		result, err := GenerateRound(0, Motif("RW"))
+
+		checks.CheckHasError(t, result, err, "circumference must be a positive integer")
+	})
+
+	t.Run("spirals until the motif lines up again", func(t *testing.T) {
+		result, err := GenerateRound(4, Motif("RRW"))
+
+		checks.CheckHasNoError(t, result, err)
+		checks.CheckNestedSlicesEqual(t, result.Rows, parseRows("RRWR", "RWRR", "WRRW"))
+		expected := []string{
+			"WRRW",
+			"RRWR",
+			"RWRR",
+		}
+		checks.CheckSlicesEqual(t, chartStrings(result.Chart()), expected)
+	})
+}
+
+func TestFloats(t *testing.T) {
+	t.Run("short floats are not reported", func(t *testing.T) {
+		fabric := Fabric{Rows: parseRows("RWWR", "RWWR")}
+
+		result := fabric.Floats(2)
+
+		checks.CheckSliceEmpty(t, result)
+	})
+
+	t.Run("finds long floats within a row", func(t *testing.T) {
+		fabric := Fabric{Rows: parseRows("RWWWR", "RRRRR")}
+
+		result := fabric.Floats(2)
+
+		checks.CheckSlicesEqual(t, result, []Float{{Row: 1, Start: 2, Length: 3, Color: 'R'}})
+	})
+
+	t.Run("columns of wrong side rows count from the right edge", func(t *testing.T) {
+		fabric := Fabric{Rows: parseRows("RRRRRR", "RWWWRR")}
+
+		result := fabric.Floats(2)
+
+		checks.CheckSlicesEqual(t, result, []Float{{Row: 2, Start: 5, Length: 3, Color: 'R'}})
+	})
+
+	t.Run("flat work carries yarn to the edges", func(t *testing.T) {
+		fabric := Fabric{Rows: parseRows("WWWR", "RWWW")}
+
+		result := fabric.Floats(2)
+
+		expected := []Float{
+			{Row: 1, Start: 1, Length: 3, Color: 'R'},
+			{Row: 2, Start: 3, Length: 3, Color: 'R'},
+		}
+		checks.CheckSlicesEqual(t, result, expected)
+	})
+
+	t.Run("unused yarns are carried up the edge", func(t *testing.T) {
+		fabric := Fabric{Rows: parseRows("RRRR", "WWWW")}
+
+		result := fabric.Floats(2)
+
+		checks.CheckSliceEmpty(t, result)
+	})
+
+	t.Run("round work floats continue into the next round", func(t *testing.T) {
+		fabric := Fabric{Rows: parseRows("WWWR", "RWWW"), Round: true}
+
+		result := fabric.Floats(2)
+
+		checks.CheckSlicesEqual(t, result, []Float{{Row: 2, Start: 2, Length: 6, Color: 'R'}})
+	})
+}
@@ -0,0 +1,371 @@
+package curl
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ptrgags/mindless-stitchcraft/knitting"
+)
+
+// Size of the square windows used to classify regions of the fabric
+const WindowSize = 4
+
+// How many rows or columns along each edge decide whether it curls
+const edgeDepth = 3
+
+// Regions with at least this fraction of knits (or purls) behave like
+// stockinette
+const stockinetteThreshold = 0.75
+
+// Diagonal lines are only a concern when neighbors along one diagonal
+// match this much more often than along the other
+const biasThreshold = 0.3
+
+// Whether a stitch looks like a knit or a purl on the front of the fabric.
+// Yarn overs are holes, so they are neither.
+var knitLike = map[knitting.KnitStitch]bool{
+	knitting.Knit:        true,
+	knitting.Purl:        false,
+	knitting.KnitTwisted: true,
+	knitting.PurlTwisted: false,
+	knitting.SlipWyib:    true,
+	knitting.SlipWyif:    false,
+	knitting.K2tog:       true,
+	knitting.P2tog:       false,
+	knitting.Ssk:         true,
+	knitting.Ssp:         false,
+	knitting.Sk2p:        true,
+	knitting.Sssp:        false,
+}
+
+// How a region of fabric behaves, judging from its knits and purls
+type Texture int
+
+const (
+	// Mostly knits. It curls.
+	Stockinette Texture = iota
+	// Mostly purls. It curls the opposite way from stockinette.
+	ReverseStockinette
+	// Rows of knits and rows of purls. It lies flat.
+	Garter
+	// Columns of knits and columns of purls. It lies flat but pulls in.
+	Rib
+	// Knits and purls alternate both ways. It lies flat.
+	Seed
+	// A mix of knits and purls without a clear structure
+	Mixed
+)
+
+func (texture Texture) String() string {
+	switch texture {
+	case Stockinette:
+		return "stockinette-like"
+	case ReverseStockinette:
+		return "reverse stockinette-like"
+	case Garter:
+		return "garter-like"
+	case Rib:
+		return "rib-like"
+	case Seed:
+		return "seed-like"
+	default:
+		return "mixed"
+	}
+}
+
+// True for textures that curl at the edges
+func (texture Texture) Curls() bool {
+	return texture == Stockinette || texture == ReverseStockinette
+}
+
+// How many stitches of a region are knits
+type Balance struct {
+	Knits int
+	// Stitches that are knits or purls. Yarn overs are not counted.
+	Total int
+}
+
+// The fraction of stitches that are knits, or 0.5 if there are none
+func (balance Balance) Fraction() float64 {
+	if balance.Total == 0 {
+		return 0.5
+	}
+
+	return float64(balance.Knits) / float64(balance.Total)
+}
+
+func (balance *Balance) add(stitch knitting.KnitStitch) {
+	knit, ok := knitLike[stitch]
+	if !ok {
+		return
+	}
+
+	balance.Total++
+	if knit {
+		balance.Knits++
+	}
+}
+
+// A rectangle of the chart. Rows count from 0 at the bottom, columns from
+// 0 at the left.
+type region struct {
+	row     int
+	column  int
+	rows    int
+	columns int
+}
+
+// Look up a stitch, counting rows from the bottom of the chart
+func stitchAt(chart knitting.Fabric, row int, column int) knitting.KnitStitch {
+	return chart[len(chart)-1-row][column]
+}
+
+func balanceOf(chart knitting.Fabric, area region) Balance {
+	balance := Balance{}
+	for r := area.row; r < area.row+area.rows; r++ {
+		for c := area.column; c < area.column+area.columns; c++ {
+			balance.add(stitchAt(chart, r, c))
+		}
+	}
+
+	return balance
+}
+
+// The fraction of neighboring pairs that differ (one knit, one purl),
+// where each stitch is compared to the one dr rows up and dc columns over
+func differences(chart knitting.Fabric, area region, dr int, dc int) float64 {
+	pairs := 0
+	differing := 0
+	for r := area.row; r+dr < area.row+area.rows; r++ {
+		for c := area.column; c < area.column+area.columns; c++ {
+			if c+dc < area.column || c+dc >= area.column+area.columns {
+				continue
+			}
+
+			a, okA := knitLike[stitchAt(chart, r, c)]
+			b, okB := knitLike[stitchAt(chart, r+dr, c+dc)]
+			if !okA || !okB {
+				continue
+			}
+
+			pairs++
+			if a != b {
+				differing++
+			}
+		}
+	}
+
+	if pairs == 0 {
+		return 0
+	}
+
+	return float64(differing) / float64(pairs)
+}
+
+func classify(chart knitting.Fabric, area region) Texture {
+	knits := balanceOf(chart, area).Fraction()
+	if knits >= stockinetteThreshold {
+		return Stockinette
+	}
+
+	if knits <= 1-stockinetteThreshold {
+		return ReverseStockinette
+	}
+
+	horizontal := differences(chart, area, 0, 1)
+	vertical := differences(chart, area, 1, 0)
+	switch {
+	case horizontal < 0.35 && vertical > 0.5:
+		return Garter
+	case vertical < 0.35 && horizontal > 0.5:
+		return Rib
+	case horizontal > 0.5 && vertical > 0.5:
+		return Seed
+	default:
+		return Mixed
+	}
+}
+
+// Classify a whole chart by its knits and purls
+func Classify(chart knitting.Fabric) Texture {
+	if len(chart) == 0 || len(chart[0]) == 0 {
+		return Mixed
+	}
+
+	return classify(chart, region{0, 0, len(chart), len(chart[0])})
+}
+
+type Edge int
+
+const (
+	Bottom Edge = iota
+	Top
+	Left
+	Right
+)
+
+func (edge Edge) String() string {
+	switch edge {
+	case Bottom:
+		return "bottom"
+	case Top:
+		return "top"
+	case Left:
+		return "left"
+	default:
+		return "right"
+	}
+}
+
+// How an edge of the fabric behaves
+type EdgeCurl struct {
+	Edge    Edge
+	Texture Texture
+	Balance Balance
+}
+
+// True if the edge rolls over
+func (curl EdgeCurl) Curls() bool {
+	return curl.Texture.Curls()
+}
+
+// Which way the edge rolls. Stockinette rolls toward the front at the top
+// and bottom and toward the back at the sides. Reverse stockinette rolls
+// the other way.
+func (curl EdgeCurl) Direction() string {
+	towardFront := curl.Edge == Bottom || curl.Edge == Top
+	if curl.Texture == ReverseStockinette {
+		towardFront = !towardFront
+	}
+
+	if towardFront {
+		return "toward the front"
+	}
+	return "toward the back"
+}
+
+// The knit/purl balance and textures of a fabric
+type Report struct {
+	// Balance of each row, starting from the bottom of the chart
+	Rows []Balance
+	// Balance of each column, from left to right
+	Columns []Balance
+	// Texture of each WindowSize x WindowSize region, starting from the
+	// bottom left. Regions at the top and right edges may be smaller.
+	Regions [][]Texture
+	Edges   []EdgeCurl
+	// How much more often stitches match their neighbor up and to the
+	// right than up and to the left, from -1 to 1. Positive values mean
+	// the fabric has diagonal lines leaning right (/), negative values
+	// leaning left (\).
+	Diagonal float64
+}
+
+// Analyze a chart of the front of the fabric, with the first row at the
+// bottom
+func Analyze(chart knitting.Fabric) (Report, error) {
+	if len(chart) == 0 || len(chart[0]) == 0 {
+		return Report{}, errors.New("chart must not be empty")
+	}
+
+	height := len(chart)
+	width := len(chart[0])
+	for _, row := range chart {
+		if len(row) != width {
+			return Report{}, errors.New("every row of the chart must have the same number of stitches")
+		}
+	}
+
+	report := Report{
+		Rows:    make([]Balance, height),
+		Columns: make([]Balance, width),
+	}
+	for r := range report.Rows {
+		report.Rows[r] = balanceOf(chart, region{r, 0, 1, width})
+	}
+	for c := range report.Columns {
+		report.Columns[c] = balanceOf(chart, region{0, c, height, 1})
+	}
+
+	for r := 0; r < height; r += WindowSize {
+		row := []Texture{}
+		for c := 0; c < width; c += WindowSize {
+			area := region{r, c, min(WindowSize, height-r), min(WindowSize, width-c)}
+			row = append(row, classify(chart, area))
+		}
+		report.Regions = append(report.Regions, row)
+	}
+
+	rowDepth := min(edgeDepth, height)
+	columnDepth := min(edgeDepth, width)
+	edges := []struct {
+		edge Edge
+		area region
+	}{
+		{Bottom, region{0, 0, rowDepth, width}},
+		{Top, region{height - rowDepth, 0, rowDepth, width}},
+		{Left, region{0, 0, height, columnDepth}},
+		{Right, region{0, width - columnDepth, height, columnDepth}},
+	}
+	for _, edge := range edges {
+		report.Edges = append(report.Edges, EdgeCurl{
+			Edge:    edge.edge,
+			Texture: classify(chart, edge.area),
+			Balance: balanceOf(chart, edge.area),
+		})
+	}
+
+	whole := region{0, 0, height, width}
+	report.Diagonal = differences(chart, whole, 1, -1) - differences(chart, whole, 1, 1)
+
+	return report, nil
+}
+
+// The fraction of regions with the given texture
+func (report Report) RegionFraction(texture Texture) float64 {
+	count := 0
+	total := 0
+	for _, row := range report.Regions {
+		for _, region := range row {
+			total++
+			if region == texture {
+				count++
+			}
+		}
+	}
+
+	return float64(count) / float64(total)
+}
+
+// Warnings about curling edges and bias, or an empty list if the fabric
+// should lie flat
+func (report Report) Warnings() []string {
+	result := []string{}
+	for _, edge := range report.Edges {
+		if !edge.Curls() {
+			continue
+		}
+
+		result = append(result, fmt.Sprintf(
+			"Warning: the %s edge is %s (%.0f%% knits) and will curl %s",
+			edge.Edge,
+			edge.Texture,
+			100*edge.Balance.Fraction(),
+			edge.Direction(),
+		))
+	}
+
+	curling := report.RegionFraction(Stockinette) + report.RegionFraction(ReverseStockinette)
+	if curling >= 0.5 {
+		result = append(result, fmt.Sprintf("Warning: %.0f%% of the fabric is stockinette-like, so it will roll up", 100*curling))
+	}
+
+	switch {
+	case report.Diagonal >= biasThreshold:
+		result = append(result, "Warning: strong diagonal lines leaning right (/), the fabric may bias")
+	case report.Diagonal <= -biasThreshold:
+		result = append(result, "Warning: strong diagonal lines leaning left (\\), the fabric may bias")
+	}
+
+	return result
+}
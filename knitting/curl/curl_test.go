@@ -0,0 +1,144 @@
+package curl
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ptrgags/mindless-stitchcraft/checks"
+	"github.com/ptrgags/mindless-stitchcraft/knitting"
+)
+
+func parseChart(t *testing.T, rows ...string) knitting.Fabric {
+	result := make(knitting.Fabric, len(rows))
+	for i, row := range rows {
+		motif, err := knitting.ParseMotif(row)
+		if err != nil {
+			t.Fatal(err)
+		}
+		result[i] = knitting.Row(motif)
+	}
+
+	return result
+}
+
+func TestClassify(t *testing.T) {
+	cases := []struct {
+		label    string
+		chart    []string
+		expected Texture
+	}{
+		{"stockinette", []string{"vvvv", "vvvv", "vvvv", "vvvv"}, Stockinette},
+		{"reverse stockinette", []string{"----", "----", "----", "----"}, ReverseStockinette},
+		{"garter", []string{"vvvv", "----", "vvvv", "----"}, Garter},
+		{"rib", []string{"v-v-", "v-v-", "v-v-", "v-v-"}, Rib},
+		{"seed", []string{"v-v-", "-v-v", "v-v-", "-v-v"}, Seed},
+		{"mixed", []string{"vv--", "v--v", "--vv", "-vv-"}, Mixed},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.label, func(t *testing.T) {
+			result := Classify(parseChart(t, tc.chart...))
+
+			if result != tc.expected {
+				t.Errorf("expected %v, got %v", tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestBalance(t *testing.T) {
+	t.Run("yarn overs are not counted", func(t *testing.T) {
+		chart := parseChart(t, "vo-/")
+
+		report, err := Analyze(chart)
+
+		checks.CheckHasNoError(t, report, err)
+		if report.Rows[0] != (Balance{Knits: 2, Total: 3}) {
+			t.Errorf("expected 2 knits out of 3, got %v", report.Rows[0])
+		}
+	})
+
+	t.Run("empty balance counts as even", func(t *testing.T) {
+		if (Balance{}).Fraction() != 0.5 {
+			t.Errorf("expected 0.5, got %v", (Balance{}).Fraction())
+		}
+	})
+}
+
+func TestAnalyze(t *testing.T) {
+	t.Run("empty chart returns error", func(t *testing.T) {
+		result, err := Analyze(knitting.Fabric{})
+
+		checks.CheckHasError(t, result, err, "chart must not be empty")
+	})
+
+	t.Run("ragged chart returns error", func(t *testing.T) {
+		result, err := Analyze(parseChart(t, "vv", "v"))
+
+		checks.CheckHasError(t, result, err, "every row of the chart must have the same number of stitches")
+	})
+
+	t.Run("rows count from the bottom", func(t *testing.T) {
+		chart := parseChart(t, "----", "vvvv")
+
+		report, err := Analyze(chart)
+
+		checks.CheckHasNoError(t, report, err)
+		if report.Rows[0].Knits != 4 || report.Rows[1].Knits != 0 {
+			t.Errorf("expected the bottom row to be all knits, got %v", report.Rows)
+		}
+	})
+
+	t.Run("classifies windows", func(t *testing.T) {
+		chart := parseChart(
+			t,
+			"vvvvv-v-",
+			"vvvv-v-v",
+			"vvvvv-v-",
+			"vvvv-v-v",
+		)
+
+		report, err := Analyze(chart)
+
+		checks.CheckHasNoError(t, report, err)
+		checks.CheckNestedSlicesEqual(t, report.Regions, [][]Texture{{Stockinette, Seed}})
+	})
+
+	t.Run("stockinette curls on every edge", func(t *testing.T) {
+		chart := parseChart(t, "vvvvvv", "vvvvvv", "vvvvvv", "vvvvvv", "vvvvvv", "vvvvvv")
+
+		report, err := Analyze(chart)
+
+		checks.CheckHasNoError(t, report, err)
+		expected := []string{
+			"Warning: the bottom edge is stockinette-like (100% knits) and will curl toward the front",
+			"Warning: the top edge is stockinette-like (100% knits) and will curl toward the front",
+			"Warning: the left edge is stockinette-like (100% knits) and will curl toward the back",
+			"Warning: the right edge is stockinette-like (100% knits) and will curl toward the back",
+			"Warning: 100% of the fabric is stockinette-like, so it will roll up",
+		}
+		checks.CheckSlicesEqual(t, report.Warnings(), expected)
+	})
+
+	t.Run("seed stitch lies flat", func(t *testing.T) {
+		chart := parseChart(t, "v-v-v-", "-v-v-v", "v-v-v-", "-v-v-v")
+
+		report, err := Analyze(chart)
+
+		checks.CheckHasNoError(t, report, err)
+		checks.CheckSliceEmpty(t, report.Warnings())
+	})
+
+	t.Run("diagonal lines may bias", func(t *testing.T) {
+		chart := parseChart(t, "-v--", "v--v", "--v-", "-v--")
+
+		report, err := Analyze(chart)
+
+		checks.CheckHasNoError(t, report, err)
+		warnings := report.Warnings()
+		last := warnings[len(warnings)-1]
+		if !strings.Contains(last, "leaning right (/)") {
+			t.Errorf("expected a warning about diagonal lines leaning right, got %v", warnings)
+		}
+	})
+}
@@ -0,0 +1,171 @@
+package doubleknit
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ptrgags/mindless-stitchcraft/knitting"
+)
+
+// A stitch of double knitting, worked with one of the two colors of yarn
+type PairedStitch struct {
+	Stitch knitting.KnitStitch
+	Color  rune
+}
+
+// A double knitting pattern. Every cell of the chart is a pair of
+// stitches: a knit stitch on the face toward the knitter and a purl stitch
+// that becomes a knit stitch on the other face. The two faces always show
+// opposite colors.
+type Pattern struct {
+	// Chart of the front face, with the first row at the bottom. Knits
+	// ('v') show colors[0] and purls ('-') show colors[1].
+	Front  knitting.Fabric
+	Colors [2]rune
+}
+
+// Make a pattern from a chart of the front face like the ones printed by
+// knit-zigzag and knit-sync, with the first row at the bottom.
+func New(front knitting.Fabric, colors [2]rune) (Pattern, error) {
+	if len(front) == 0 {
+		return Pattern{}, errors.New("chart must not be empty")
+	}
+
+	if colors[0] == colors[1] {
+		return Pattern{}, errors.New("colors must be different")
+	}
+
+	width := len(front[0])
+	for i, row := range front {
+		if len(row) != width {
+			return Pattern{}, fmt.Errorf("chart row %d has %d stitches, expected %d", len(front)-i, len(row), width)
+		}
+
+		for _, stitch := range row {
+			if stitch != knitting.Knit && stitch != knitting.Purl {
+				return Pattern{}, fmt.Errorf("double knitting charts must only use knits (v) and purls (-), got %c", stitch.ToRune())
+			}
+		}
+	}
+
+	return Pattern{front, colors}, nil
+}
+
+// Make a chart where each motif is one row repeated to the full width.
+// The first motif is the bottom row.
+func ChartFromMotifs(width int, motifs []knitting.Motif) knitting.Fabric {
+	chart := make(knitting.Fabric, len(motifs))
+	for i, motif := range motifs {
+		chart[len(motifs)-1-i] = knitting.Row(motif.RepeatToLength(uint(width)))
+	}
+
+	return chart
+}
+
+// Chart of the back face, as seen when the fabric is flipped over from
+// side to side. It is the front face mirrored with the colors swapped.
+func (pattern Pattern) Back() knitting.Fabric {
+	return pattern.Front.ReverseFace()
+}
+
+func (pattern Pattern) color(stitch knitting.KnitStitch) rune {
+	if stitch == knitting.Knit {
+		return pattern.Colors[0]
+	}
+
+	return pattern.Colors[1]
+}
+
+func (pattern Pattern) colorChart(face knitting.Fabric) [][]rune {
+	result := make([][]rune, len(face))
+	for i, row := range face {
+		result[i] = make([]rune, len(row))
+		for j, stitch := range row {
+			result[i][j] = pattern.color(stitch)
+		}
+	}
+
+	return result
+}
+
+// The colors of the front face, with the first row at the bottom
+func (pattern Pattern) FrontColors() [][]rune {
+	return pattern.colorChart(pattern.Front)
+}
+
+// The colors of the back face, with the first row at the bottom
+func (pattern Pattern) BackColors() [][]rune {
+	return pattern.colorChart(pattern.Back())
+}
+
+// The stitches of a row (counting from 0) in the order they are worked.
+// The front face is toward the knitter on even rows and the back face on
+// odd rows. For each cell of the face toward the knitter, from right to
+// left, knit with that cell's color, then purl with the other color.
+func (pattern Pattern) Row(row int) []PairedStitch {
+	face := pattern.Front
+	if row%2 == 1 {
+		face = pattern.Back()
+	}
+
+	chartRow := face[len(face)-1-row%len(face)]
+	result := make([]PairedStitch, 0, 2*len(chartRow))
+	for i := len(chartRow) - 1; i >= 0; i-- {
+		stitch := chartRow[i]
+		result = append(
+			result,
+			PairedStitch{knitting.Knit, pattern.color(stitch)},
+			PairedStitch{knitting.Purl, pattern.color(stitch.Swap())},
+		)
+	}
+
+	return result
+}
+
+// Write a row of stitch pairs, combining repeated pairs, e.g.
+// "(k1 A, p1 B) 3 times, (k1 B, p1 A)"
+func formatPairs(stitches []PairedStitch) string {
+	parts := []string{}
+	for i := 0; i < len(stitches); {
+		pair := stitches[i : i+2]
+		count := 1
+		for i+2*count < len(stitches) && stitches[i+2*count] == pair[0] {
+			count++
+		}
+
+		part := fmt.Sprintf("(k1 %c, p1 %c)", pair[0].Color, pair[1].Color)
+		if count > 1 {
+			part = fmt.Sprintf("%s %d times", part, count)
+		}
+		parts = append(parts, part)
+		i += 2 * count
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// Write instructions for one repeat of the chart. Every row holds both
+// yarns and works each cell as a pair of stitches. The work is turned at
+// the end of every row, so the faces take turns facing the knitter.
+func (pattern Pattern) Instructions() []string {
+	// Rows alternate faces, so an odd number of chart rows must be worked
+	// twice to get back to the front face.
+	rows := len(pattern.Front)
+	if rows%2 == 1 {
+		rows *= 2
+	}
+
+	result := make([]string, 0, rows+1)
+	for row := 0; row < rows; row++ {
+		label := "front facing"
+		if row%2 == 1 {
+			label = "back facing"
+		}
+
+		result = append(result, fmt.Sprintf("Row %d (%s): %s", row+1, label, formatPairs(pattern.Row(row))))
+	}
+	result = append(result, fmt.Sprintf("Repeat rows 1-%d.", rows))
+
+	return result
+}
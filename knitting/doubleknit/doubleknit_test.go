@@ -0,0 +1,133 @@
+package doubleknit
+
+import (
+	"testing"
+
+	"github.com/ptrgags/mindless-stitchcraft/checks"
+	"github.com/ptrgags/mindless-stitchcraft/knitting"
+)
+
+func parseChart(t *testing.T, rows ...string) knitting.Fabric {
+	result := make(knitting.Fabric, len(rows))
+	for i, row := range rows {
+		motif, err := knitting.ParseMotif(row)
+		if err != nil {
+			t.Fatal(err)
+		}
+		result[i] = knitting.Row(motif)
+	}
+
+	return result
+}
+
+func chartStrings(chart [][]rune) []string {
+	result := make([]string, len(chart))
+	for i, row := range chart {
+		result[i] = string(row)
+	}
+
+	return result
+}
+
+var colors = [2]rune{'A', 'B'}
+
+func TestNew(t *testing.T) {
+	t.Run("empty chart returns error", func(t *testing.T) {
+		result, err := New(knitting.Fabric{}, colors)
+
+		checks.CheckHasError(t, result, err, "chart must not be empty")
+	})
+
+	t.Run("matching colors return error", func(t *testing.T) {
+		result, err := New(parseChart(t, "v-"), [2]rune{'A', 'A'})
+
+		checks.CheckHasError(t, result, err, "colors must be different")
+	})
+
+	t.Run("ragged chart returns error", func(t *testing.T) {
+		result, err := New(parseChart(t, "v-", "v"), colors)
+
+		checks.CheckHasError(t, result, err, "chart row 1 has 1 stitches, expected 2")
+	})
+
+	t.Run("stitches other than knits and purls return error", func(t *testing.T) {
+		result, err := New(parseChart(t, "v/"), colors)
+
+		checks.CheckHasError(t, result, err, "double knitting charts must only use knits (v) and purls (-), got /")
+	})
+}
+
+func TestChartFromMotifs(t *testing.T) {
+	motifA, _ := knitting.ParseMotif("v-")
+	motifB, _ := knitting.ParseMotif("vv-")
+
+	result := ChartFromMotifs(4, []knitting.Motif{motifA, motifB})
+
+	checks.CheckSlicesEqual(t, result.ToStrings(), []string{"vv-v", "v-v-"})
+}
+
+func TestFaces(t *testing.T) {
+	pattern, _ := New(parseChart(t, "vv-", "v--"), colors)
+
+	t.Run("front shows knits in the first color", func(t *testing.T) {
+		checks.CheckSlicesEqual(t, chartStrings(pattern.FrontColors()), []string{"AAB", "ABB"})
+	})
+
+	t.Run("back is mirrored with colors swapped", func(t *testing.T) {
+		checks.CheckSlicesEqual(t, chartStrings(pattern.BackColors()), []string{"ABB", "AAB"})
+	})
+}
+
+func TestRow(t *testing.T) {
+	pattern, _ := New(parseChart(t, "vv-", "v--"), colors)
+
+	t.Run("front facing rows follow the front from right to left", func(t *testing.T) {
+		result := pattern.Row(0)
+
+		expected := []PairedStitch{
+			{knitting.Knit, 'B'}, {knitting.Purl, 'A'},
+			{knitting.Knit, 'B'}, {knitting.Purl, 'A'},
+			{knitting.Knit, 'A'}, {knitting.Purl, 'B'},
+		}
+		checks.CheckSlicesEqual(t, result, expected)
+	})
+
+	t.Run("back facing rows follow the back from right to left", func(t *testing.T) {
+		result := pattern.Row(1)
+
+		expected := []PairedStitch{
+			{knitting.Knit, 'B'}, {knitting.Purl, 'A'},
+			{knitting.Knit, 'B'}, {knitting.Purl, 'A'},
+			{knitting.Knit, 'A'}, {knitting.Purl, 'B'},
+		}
+		checks.CheckSlicesEqual(t, result, expected)
+	})
+}
+
+func TestInstructions(t *testing.T) {
+	t.Run("combines repeated pairs", func(t *testing.T) {
+		pattern, _ := New(parseChart(t, "vv-", "v--"), colors)
+
+		result := pattern.Instructions()
+
+		expected := []string{
+			"Row 1 (front facing): (k1 B, p1 A) 2 times, (k1 A, p1 B)",
+			"Row 2 (back facing): (k1 B, p1 A) 2 times, (k1 A, p1 B)",
+			"Repeat rows 1-2.",
+		}
+		checks.CheckSlicesEqual(t, result, expected)
+	})
+
+	t.Run("odd charts are worked twice to return to the front", func(t *testing.T) {
+		pattern, _ := New(parseChart(t, "v-"), colors)
+
+		result := pattern.Instructions()
+
+		expected := []string{
+			"Row 1 (front facing): (k1 B, p1 A), (k1 A, p1 B)",
+			"Row 2 (back facing): (k1 B, p1 A), (k1 A, p1 B)",
+			"Repeat rows 1-2.",
+		}
+		checks.CheckSlicesEqual(t, result, expected)
+	})
+}
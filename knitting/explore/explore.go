@@ -0,0 +1,255 @@
+package explore
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"sort"
+	"sync"
+
+	"github.com/ptrgags/mindless-stitchcraft/knitting"
+	knitsync "github.com/ptrgags/mindless-stitchcraft/knitting/sync"
+	"github.com/ptrgags/mindless-stitchcraft/knitting/zigzag"
+)
+
+// The search space doubles with each stitch, so keep it reasonable
+const MaxMotifLength = 24
+
+// How many motifs each worker checks at a time
+const chunkSize = 4096
+
+// The result of running a single motif through the generators
+type Result struct {
+	Motif knitting.Motif
+	// Height of the zigzag pattern
+	ZigzagRows int
+	// Height of the sync pattern
+	SyncRows int
+	// How many knits are in the motif. The rest are purls
+	Knits int
+}
+
+// How far the motif is from having the same number of knits and purls.
+// 0 is perfectly balanced, 1 is all knits or all purls
+func (result Result) Imbalance() float64 {
+	n := len(result.Motif)
+	purls := n - result.Knits
+	difference := result.Knits - purls
+	if difference < 0 {
+		difference = -difference
+	}
+
+	return float64(difference) / float64(n)
+}
+
+// Motifs of knits and purls are stored as bit masks while searching.
+// The first stitch is the most significant bit, and bits are set for
+// purls. This way, comparing masks compares motifs alphabetically with
+// knits before purls.
+func toMotif(mask uint32, length int) knitting.Motif {
+	result := make(knitting.Motif, length)
+	for i := range result {
+		if mask&(1<<(length-1-i)) != 0 {
+			result[i] = knitting.Purl
+		} else {
+			result[i] = knitting.Knit
+		}
+	}
+
+	return result
+}
+
+// Rotate the motif by one stitch
+func rotate(mask uint32, length int, all uint32) uint32 {
+	return ((mask >> 1) | (mask << (length - 1))) & all
+}
+
+func reverse(mask uint32, length int) uint32 {
+	result := uint32(0)
+	for i := 0; i < length; i++ {
+		if mask&(1<<i) != 0 {
+			result |= 1 << (length - 1 - i)
+		}
+	}
+
+	return result
+}
+
+// A motif is primitive if it is not a shorter motif repeated several
+// times. E.g. v-v- is just v- twice, so it makes the same fabric.
+func isPrimitive(mask uint32, length int, all uint32) bool {
+	rotated := mask
+	for i := 1; i < length; i++ {
+		rotated = rotate(rotated, length, all)
+		if rotated == mask {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Check if this motif is the one representative of all the motifs that
+// produce the same fabric: rotations of the motif (starting at a
+// different stitch), the motif read backwards, and the motif with knits
+// and purls swapped. The representative is the smallest bit mask.
+func isCanonical(mask uint32, length int) bool {
+	all := uint32(1)<<length - 1
+	variants := []uint32{
+		mask,
+		reverse(mask, length),
+		^mask & all,
+		^reverse(mask, length) & all,
+	}
+
+	for _, variant := range variants {
+		rotated := variant
+		for i := 0; i < length; i++ {
+			if rotated < mask {
+				return false
+			}
+			rotated = rotate(rotated, length, all)
+		}
+	}
+
+	return isPrimitive(mask, length, all)
+}
+
+func countKnits(motif knitting.Motif) int {
+	knits := 0
+	for _, stitch := range motif {
+		if stitch == knitting.Knit {
+			knits++
+		}
+	}
+
+	return knits
+}
+
+func evaluate(motif knitting.Motif, fabricWidth int) (Result, error) {
+	zigzagFabric, err := zigzag.GenerateZigzagFabric(motif, fabricWidth)
+	if err != nil {
+		return Result{}, err
+	}
+
+	syncFabric, err := knitsync.GenerateFabric(uint(fabricWidth), []knitting.Motif{motif})
+	if err != nil {
+		return Result{}, err
+	}
+
+	return Result{
+		Motif:      motif,
+		ZigzagRows: len(zigzagFabric),
+		SyncRows:   len(syncFabric),
+		Knits:      countKnits(motif),
+	}, nil
+}
+
+// A range of bit masks for motifs of the same length
+type chunk struct {
+	length int
+	start  uint32
+	end    uint32
+}
+
+func searchChunk(job chunk, fabricWidth int) []Result {
+	results := []Result{}
+	for mask := job.start; mask < job.end; mask++ {
+		if !isCanonical(mask, job.length) {
+			continue
+		}
+
+		// The fabric width was already validated, so this can't fail
+		result, _ := evaluate(toMotif(mask, job.length), fabricWidth)
+		results = append(results, result)
+	}
+
+	return results
+}
+
+func makeChunks(maxLength int) []chunk {
+	chunks := []chunk{}
+	for length := 1; length <= maxLength; length++ {
+		count := uint32(1) << length
+		for start := uint32(0); start < count; start += chunkSize {
+			end := start + chunkSize
+			if end > count {
+				end = count
+			}
+			chunks = append(chunks, chunk{length, start, end})
+		}
+	}
+
+	return chunks
+}
+
+// Order results from most to least promising: short repeats first, then
+// short motifs, then motifs with a balance of knits and purls.
+func rankResults(results []Result) {
+	sort.Slice(results, func(i, j int) bool {
+		a := results[i]
+		b := results[j]
+		if a.ZigzagRows != b.ZigzagRows {
+			return a.ZigzagRows < b.ZigzagRows
+		}
+
+		if len(a.Motif) != len(b.Motif) {
+			return len(a.Motif) < len(b.Motif)
+		}
+
+		if a.Imbalance() != b.Imbalance() {
+			return a.Imbalance() < b.Imbalance()
+		}
+
+		return knitting.Row(a.Motif).ToString() < knitting.Row(b.Motif).ToString()
+	})
+}
+
+// Enumerate every motif of knits and purls up to maxLength stitches and
+// run each one through the zigzag and sync generators for a fabric
+// fabricWidth stitches wide.
+//
+// Motifs that produce the same fabric (rotations, reversals and
+// knit/purl swaps, as well as motifs that are a shorter motif repeated)
+// are only listed once. The search is split across all CPU cores.
+//
+// Results are ranked by zigzag repeat height, then motif length, then
+// balance between knits and purls.
+func Explore(fabricWidth int, maxLength int) ([]Result, error) {
+	if fabricWidth < 1 {
+		return []Result{}, errors.New("fabricWidth must be a positive integer")
+	}
+
+	if maxLength < 1 || maxLength > MaxMotifLength {
+		return []Result{}, fmt.Errorf("maxLength must be between 1 and %d", MaxMotifLength)
+	}
+
+	chunks := makeChunks(maxLength)
+	jobs := make(chan chunk, len(chunks))
+	for _, job := range chunks {
+		jobs <- job
+	}
+	close(jobs)
+
+	found := make(chan []Result, len(chunks))
+	var workers sync.WaitGroup
+	for i := 0; i < runtime.NumCPU(); i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for job := range jobs {
+				found <- searchChunk(job, fabricWidth)
+			}
+		}()
+	}
+	workers.Wait()
+	close(found)
+
+	results := []Result{}
+	for chunkResults := range found {
+		results = append(results, chunkResults...)
+	}
+
+	rankResults(results)
+	return results, nil
+}
@@ -0,0 +1,109 @@
+package explore
+
+import (
+	"testing"
+
+	"github.com/ptrgags/mindless-stitchcraft/checks"
+	"github.com/ptrgags/mindless-stitchcraft/knitting"
+)
+
+func motifStrings(results []Result) []string {
+	motifs := make([]string, len(results))
+	for i, result := range results {
+		motifs[i] = knitting.Row(result.Motif).ToString()
+	}
+
+	return motifs
+}
+
+func TestExplore(t *testing.T) {
+	t.Run("invalid fabricWidth returns error", func(t *testing.T) {
+		result, err := Explore(0, 3)
+
+		checks.CheckHasError(t, result, err, "fabricWidth must be a positive integer")
+	})
+
+	t.Run("invalid maxLength returns error", func(t *testing.T) {
+		cases := []struct {
+			label     string
+			maxLength int
+		}{
+			{"zero", 0},
+			{"too long", MaxMotifLength + 1},
+		}
+
+		for _, tc := range cases {
+			t.Run(tc.label, func(t *testing.T) {
+				result, err := Explore(10, tc.maxLength)
+
+				checks.CheckHasError(t, result, err, "maxLength must be between 1 and 24")
+			})
+		}
+	})
+
+	t.Run("equivalent motifs are only listed once", func(t *testing.T) {
+		// Up to length 3, there is only one motif of each length once
+		// rotations, reversals, swaps and repeats are removed.
+		results, err := Explore(10, 3)
+
+		checks.CheckHasNoError(t, results, err)
+		checks.CheckSlicesEqual(t, motifStrings(results), []string{"v", "v-", "vv-"})
+	})
+
+	t.Run("counts distinct motifs of length 4", func(t *testing.T) {
+		// The only new motifs of length 4 are vvv- and vv--. The others
+		// are rotations, swaps, or repeats of v-
+		results, err := Explore(10, 4)
+
+		checks.CheckHasNoError(t, results, err)
+		if len(results) != 5 {
+			t.Errorf("Expected 5 results, got %v", motifStrings(results))
+		}
+	})
+
+	t.Run("results are ranked by zigzag height", func(t *testing.T) {
+		results, err := Explore(7, 6)
+
+		checks.CheckHasNoError(t, results, err)
+		for i := 1; i < len(results); i++ {
+			if results[i-1].ZigzagRows > results[i].ZigzagRows {
+				t.Errorf("Results out of order at %d: %v", i, motifStrings(results))
+			}
+		}
+	})
+
+	t.Run("results include generated pattern sizes", func(t *testing.T) {
+		results, err := Explore(10, 3)
+
+		checks.CheckHasNoError(t, results, err)
+		expected := Result{Motif: knitting.Motif{knitting.Knit, knitting.Knit, knitting.Purl}, ZigzagRows: 6, SyncRows: 2, Knits: 2}
+		last := results[len(results)-1]
+		checks.CheckSlicesEqual(t, last.Motif, expected.Motif)
+		if last.ZigzagRows != expected.ZigzagRows || last.SyncRows != expected.SyncRows || last.Knits != expected.Knits {
+			t.Errorf("Expected %+v, got %+v", expected, last)
+		}
+	})
+}
+
+func TestResultImbalance(t *testing.T) {
+	cases := []struct {
+		label    string
+		motif    string
+		expected float64
+	}{
+		{"balanced", "v--v", 0},
+		{"all knits", "vvv", 1},
+		{"mostly purls", "v---", 0.5},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.label, func(t *testing.T) {
+			motif, _ := knitting.ParseMotif(tc.motif)
+			result := Result{Motif: motif, Knits: countKnits(motif)}
+
+			if result.Imbalance() != tc.expected {
+				t.Errorf("Expected %v, got %v", tc.expected, result.Imbalance())
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package knitting
 
 import (
+	"fmt"
 	"slices"
 )
 
@@ -25,6 +26,24 @@ func (row Row) Reverse() Row {
 	return result
 }
 
+// Count how many stitches are worked off the left needle in this row.
+func (row Row) StitchesConsumed() int {
+	total := 0
+	for _, stitch := range row {
+		total += stitch.StitchesConsumed()
+	}
+	return total
+}
+
+// Count how many stitches are on the right needle at the end of this row.
+func (row Row) StitchesProduced() int {
+	total := 0
+	for _, stitch := range row {
+		total += stitch.StitchesProduced()
+	}
+	return total
+}
+
 func (row Row) ToString() string {
 	runes := make([]rune, len(row))
 	for i, stitch := range row {
@@ -33,6 +52,43 @@ func (row Row) ToString() string {
 	return string(runes)
 }
 
+// Pass in rows of the fabric listed as knit ('v') and purl ('-') as the
+// knitter works them.
+//
+// However, every second row is knit on the wrong side of the fabric, so
+// the direction of stitching is reversed, and also knits show up as purls
+// and vice-versa on the front. This function takes care of this.
+//
+// The input fabric is treated as immutable, so a new fabric is allocated.
+func (fabric Fabric) HandleReverseRows() Fabric {
+	result := ReverseAlternateRows(fabric)
+	for i := 1; i < len(result); i += 2 {
+		result[i] = result[i].SwapKnitsAndPurls()
+	}
+
+	return result
+}
+
+// Reverse every second row of a grid, starting with the second row. This
+// is how the rows of flat knitting line up when the work is turned at the
+// end of every row. The rows that are not reversed are shared with the
+// input, reversed rows are newly allocated.
+func ReverseAlternateRows[R ~[]T, T any](rows []R) []R {
+	result := make([]R, len(rows))
+	for i, row := range rows {
+		if i%2 == 0 {
+			result[i] = row
+			continue
+		}
+
+		reversed := slices.Clone(row)
+		slices.Reverse(reversed)
+		result[i] = reversed
+	}
+
+	return result
+}
+
 func (fabric Fabric) Rotate180() Fabric {
 	n := len(fabric)
 	rotated := make(Fabric, n)
@@ -43,6 +99,95 @@ func (fabric Fabric) Rotate180() Fabric {
 	return rotated
 }
 
+// Compute how the other face of the fabric looks when the fabric is
+// flipped over from side to side. Each row is mirrored, and knits and purls
+// swap since a knit stitch looks like a purl from behind.
+//
+// Flipping the fabric twice returns the original fabric.
+func (fabric Fabric) ReverseFace() Fabric {
+	result := make(Fabric, len(fabric))
+	for i, row := range fabric {
+		result[i] = row.Reverse().SwapKnitsAndPurls()
+	}
+
+	return result
+}
+
+// Returned by CheckConstantWidth when a row would change the width of the
+// fabric, so callers can tell it apart from other errors
+type WidthError struct {
+	// 1-based row number in stitching order
+	Row      int
+	Consumed int
+	Produced int
+	Width    int
+}
+
+func (err *WidthError) Error() string {
+	return fmt.Sprintf("row %d would change the row width: it works %d stitches and leaves %d, but the fabric is %d stitches wide", err.Row, err.Consumed, err.Produced, err.Width)
+}
+
+// Check that every row of the fabric (listed in stitching order) starts
+// and ends with exactly width stitches. Increases and decreases are only
+// allowed if they balance out within the row. The error is a *WidthError.
+func (fabric Fabric) CheckConstantWidth(width int) error {
+	for i, row := range fabric {
+		consumed := row.StitchesConsumed()
+		produced := row.StitchesProduced()
+		if consumed != width || produced != width {
+			return &WidthError{i + 1, consumed, produced, width}
+		}
+	}
+
+	return nil
+}
+
+// Like CheckConstantWidth, but for shaped fabric where each row has its own
+// width. The edge stitches that shape the fabric are not part of the rows,
+// so each row must still start and end with its own width.
+func (fabric Fabric) CheckWidths(widths []int) error {
+	for i, row := range fabric {
+		consumed := row.StitchesConsumed()
+		produced := row.StitchesProduced()
+		if consumed != widths[i] || produced != widths[i] {
+			return fmt.Errorf("row %d would change the row width: it works %d stitches and leaves %d, but the row is %d stitches wide", i+1, consumed, produced, widths[i])
+		}
+	}
+
+	return nil
+}
+
+// Repeat the rows of the fabric (listed in stitching order) until it is
+// exactly rows rows long. If rows is not a multiple of the height of the
+// fabric, the last repeat is cut off partway.
+func (fabric Fabric) RepeatRows(rows int) Fabric {
+	if len(fabric) == 0 || rows <= 0 {
+		return Fabric{}
+	}
+
+	result := make(Fabric, rows)
+	for i := range result {
+		result[i] = fabric[i%len(fabric)]
+	}
+
+	return result
+}
+
+// List the row counts up to and including rows where a full repeat of the
+// fabric ends, i.e. where a piece made by RepeatRows could end cleanly.
+func (fabric Fabric) RepeatBoundaries(rows int) []int {
+	result := []int{}
+	if len(fabric) == 0 {
+		return result
+	}
+
+	for boundary := len(fabric); boundary <= rows; boundary += len(fabric) {
+		result = append(result, boundary)
+	}
+
+	return result
+}
+
 func (fabric Fabric) ToStrings() []string {
 	result := make([]string, len(fabric))
 	for i, row := range fabric {
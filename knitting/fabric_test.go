@@ -1,6 +1,7 @@
 package knitting
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/ptrgags/mindless-stitchcraft/checks"
@@ -62,6 +63,30 @@ func TestRowReverse(t *testing.T) {
 	})
 }
 
+func TestRowStitchCounts(t *testing.T) {
+	t.Run("empty row has no stitches", func(t *testing.T) {
+		empty := Row{}
+
+		if empty.StitchesConsumed() != 0 || empty.StitchesProduced() != 0 {
+			t.Errorf("Expected 0 stitches, got (%v, %v)", empty.StitchesConsumed(), empty.StitchesProduced())
+		}
+	})
+
+	t.Run("counts stitches worked and made", func(t *testing.T) {
+		row := Row{Knit, YarnOver, K2tog, Purl, K2tog}
+
+		consumed := row.StitchesConsumed()
+		produced := row.StitchesProduced()
+
+		if consumed != 6 {
+			t.Errorf("Expected 6 stitches consumed, got %v", consumed)
+		}
+		if produced != 5 {
+			t.Errorf("Expected 5 stitches produced, got %v", produced)
+		}
+	})
+}
+
 func toStitchArray(fabric Fabric) [][]KnitStitch {
 	result := make([][]KnitStitch, len(fabric))
 	for i, row := range fabric {
@@ -71,6 +96,61 @@ func toStitchArray(fabric Fabric) [][]KnitStitch {
 	return result
 }
 
+func TestFabricHandleReverseRows(t *testing.T) {
+	t.Run("Empty fabric returns empty fabric", func(t *testing.T) {
+		empty := Fabric{}
+
+		result := empty.HandleReverseRows()
+
+		checks.CheckSliceEmpty(t, result)
+	})
+
+	t.Run("every second row is reversed and swapped", func(t *testing.T) {
+		stitchingOrder := Fabric{
+			{Knit, Purl, Purl},
+			{Knit, Purl, Purl},
+			{Knit, Knit, Purl},
+			{Knit, Knit, Purl},
+		}
+
+		result := stitchingOrder.HandleReverseRows()
+
+		expected := Fabric{
+			{Knit, Purl, Purl},
+			{Knit, Knit, Purl},
+			{Knit, Knit, Purl},
+			{Knit, Purl, Purl},
+		}
+		checks.CheckNestedSlicesEqual(t, toStitchArray(result), toStitchArray(expected))
+	})
+}
+
+func TestReverseAlternateRows(t *testing.T) {
+	t.Run("empty grid returns empty grid", func(t *testing.T) {
+		result := ReverseAlternateRows([][]rune{})
+
+		checks.CheckSliceEmpty(t, result)
+	})
+
+	t.Run("reverses every second row", func(t *testing.T) {
+		rows := [][]rune{[]rune("abc"), []rune("def"), []rune("ghi")}
+
+		result := ReverseAlternateRows(rows)
+
+		expected := [][]rune{[]rune("abc"), []rune("fed"), []rune("ghi")}
+		checks.CheckNestedSlicesEqual(t, result, expected)
+	})
+
+	t.Run("does not modify the input", func(t *testing.T) {
+		rows := [][]rune{[]rune("ab"), []rune("cd")}
+
+		ReverseAlternateRows(rows)
+
+		expected := [][]rune{[]rune("ab"), []rune("cd")}
+		checks.CheckNestedSlicesEqual(t, rows, expected)
+	})
+}
+
 func TestFabricRotate180(t *testing.T) {
 	t.Run("Empty fabric returns empty fabric", func(t *testing.T) {
 		empty := Fabric{}
@@ -98,6 +178,175 @@ func TestFabricRotate180(t *testing.T) {
 	})
 }
 
+func TestFabricReverseFace(t *testing.T) {
+	t.Run("Empty fabric returns empty fabric", func(t *testing.T) {
+		empty := Fabric{}
+
+		result := empty.ReverseFace()
+
+		checks.CheckSliceEmpty(t, result)
+	})
+
+	t.Run("mirrors rows and swaps knits and purls", func(t *testing.T) {
+		original := Fabric{
+			{Knit, Knit, Purl},
+			{Purl, Knit, Purl},
+		}
+
+		result := original.ReverseFace()
+
+		expected := Fabric{
+			{Knit, Purl, Purl},
+			{Knit, Purl, Knit},
+		}
+		checks.CheckNestedSlicesEqual(t, toStitchArray(result), toStitchArray(expected))
+	})
+
+	t.Run("Reversing twice returns the original fabric", func(t *testing.T) {
+		original := Fabric{
+			{Knit, Knit, Purl},
+			{Purl, Purl, Purl},
+		}
+
+		result := original.ReverseFace().ReverseFace()
+
+		checks.CheckNestedSlicesEqual(t, toStitchArray(result), toStitchArray(original))
+	})
+
+	t.Run("Does not modify input fabric", func(t *testing.T) {
+		original := Fabric{
+			{Knit, Knit, Purl},
+		}
+
+		original.ReverseFace()
+
+		expected := Fabric{
+			{Knit, Knit, Purl},
+		}
+		checks.CheckNestedSlicesEqual(t, toStitchArray(original), toStitchArray(expected))
+	})
+}
+
+func TestFabricCheckConstantWidth(t *testing.T) {
+	t.Run("plain knits and purls keep the width", func(t *testing.T) {
+		fabric := Fabric{
+			{Knit, Purl, Purl},
+			{Purl, Knit, Purl},
+		}
+
+		err := fabric.CheckConstantWidth(3)
+
+		checks.CheckHasNoError(t, fabric, err)
+	})
+
+	t.Run("balanced increases and decreases keep the width", func(t *testing.T) {
+		fabric := Fabric{
+			{YarnOver, K2tog, Purl},
+		}
+
+		err := fabric.CheckConstantWidth(3)
+
+		checks.CheckHasNoError(t, fabric, err)
+	})
+
+	t.Run("unbalanced row returns error", func(t *testing.T) {
+		fabric := Fabric{
+			{Knit, Knit, Knit},
+			{YarnOver, Knit, Knit},
+		}
+
+		err := fabric.CheckConstantWidth(3)
+
+		checks.CheckHasError(t, fabric, err, "row 2 would change the row width: it works 2 stitches and leaves 3, but the fabric is 3 stitches wide")
+		var widthErr *WidthError
+		if !errors.As(err, &widthErr) || widthErr.Row != 2 {
+			t.Errorf("expected a WidthError for row 2, got %v", err)
+		}
+	})
+}
+
+func TestFabricCheckWidths(t *testing.T) {
+	t.Run("rows may have different widths", func(t *testing.T) {
+		fabric := Fabric{
+			{Knit, Purl},
+			{Purl, Knit, Purl, Knit},
+		}
+
+		err := fabric.CheckWidths([]int{2, 4})
+
+		checks.CheckHasNoError(t, fabric, err)
+	})
+
+	t.Run("unbalanced row returns error", func(t *testing.T) {
+		fabric := Fabric{
+			{Knit, Knit},
+			{YarnOver, Knit, Knit},
+		}
+
+		err := fabric.CheckWidths([]int{2, 3})
+
+		checks.CheckHasError(t, fabric, err, "row 2 would change the row width: it works 2 stitches and leaves 3, but the row is 3 stitches wide")
+	})
+}
+
+func TestFabricRepeatRows(t *testing.T) {
+	t.Run("empty fabric returns empty fabric", func(t *testing.T) {
+		result := Fabric{}.RepeatRows(4)
+
+		checks.CheckSliceEmpty(t, result)
+	})
+
+	t.Run("repeats rows to full repeats", func(t *testing.T) {
+		fabric := Fabric{{Knit, Purl}, {Purl, Purl}}
+
+		result := fabric.RepeatRows(4)
+
+		expected := []string{"v-", "--", "v-", "--"}
+		checks.CheckSlicesEqual(t, result.ToStrings(), expected)
+	})
+
+	t.Run("cuts off the last repeat", func(t *testing.T) {
+		fabric := Fabric{{Knit, Purl}, {Purl, Purl}, {Knit, Knit}}
+
+		result := fabric.RepeatRows(5)
+
+		expected := []string{"v-", "--", "vv", "v-", "--"}
+		checks.CheckSlicesEqual(t, result.ToStrings(), expected)
+	})
+
+	t.Run("fewer rows than the fabric truncates it", func(t *testing.T) {
+		fabric := Fabric{{Knit}, {Purl}, {Knit}}
+
+		result := fabric.RepeatRows(2)
+
+		checks.CheckSlicesEqual(t, result.ToStrings(), []string{"v", "-"})
+	})
+}
+
+func TestFabricRepeatBoundaries(t *testing.T) {
+	t.Run("empty fabric has no boundaries", func(t *testing.T) {
+		result := Fabric{}.RepeatBoundaries(10)
+
+		checks.CheckSliceEmpty(t, result)
+	})
+
+	t.Run("lists the end of each full repeat", func(t *testing.T) {
+		fabric := Fabric{{Knit}, {Purl}, {Knit}, {Purl}}
+
+		result := fabric.RepeatBoundaries(14)
+
+		checks.CheckSlicesEqual(t, result, []int{4, 8, 12})
+	})
+
+	t.Run("includes the last row if it ends a repeat", func(t *testing.T) {
+		fabric := Fabric{{Knit}, {Purl}}
+
+		result := fabric.RepeatBoundaries(4)
+
+		checks.CheckSlicesEqual(t, result, []int{2, 4})
+	})
+}
+
 func TestFabricToStrings(t *testing.T) {
 	t.Run("Empty fabric returns empty slice", func(t *testing.T) {
 		empty := Fabric{}
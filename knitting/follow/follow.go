@@ -0,0 +1,235 @@
+package follow
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/ptrgags/mindless-stitchcraft/knitting"
+	"github.com/ptrgags/mindless-stitchcraft/knitting/instructions"
+)
+
+// Where the knitter is in a pattern, saved between sessions
+type State struct {
+	Pattern     string   `json:"pattern"`
+	FabricWidth int      `json:"fabricWidth"`
+	Motifs      []string `json:"motifs"`
+	// The current row, counting from 1. This keeps counting past the end
+	// of the pattern repeat.
+	Row int `json:"row"`
+}
+
+// Check if the state was saved for the same pattern
+func (state State) SamePattern(other State) bool {
+	return state.Pattern == other.Pattern &&
+		state.FabricWidth == other.FabricWidth &&
+		slices.Equal(state.Motifs, other.Motifs)
+}
+
+// Load the state file. If the file does not exist, the error wraps
+// os.ErrNotExist.
+func LoadState(path string) (State, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return State{}, err
+	}
+
+	state := State{}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return State{}, fmt.Errorf("invalid state file %s: %w", path, err)
+	}
+
+	return state, nil
+}
+
+func SaveState(path string, state State) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// Steps through the rows of a flat fabric as they are knit
+type Follower struct {
+	// The fabric in stitching order
+	fabric knitting.Fabric
+	// Current row, counting from 0. This may be past the end of the
+	// fabric, in which case the pattern repeats.
+	row int
+}
+
+// Start following a fabric listed in stitching order at the given row,
+// counting from 1.
+func NewFollower(stitchingOrder knitting.Fabric, row int) (*Follower, error) {
+	if len(stitchingOrder) == 0 {
+		return nil, errors.New("fabric must not be empty")
+	}
+
+	follower := &Follower{fabric: stitchingOrder}
+	if err := follower.GoTo(row); err != nil {
+		return nil, err
+	}
+
+	return follower, nil
+}
+
+// The current row, counting from 1
+func (follower *Follower) Row() int {
+	return follower.row + 1
+}
+
+// The current row within the pattern repeat, counting from 1
+func (follower *Follower) RepeatRow() int {
+	return follower.row%len(follower.fabric) + 1
+}
+
+func (follower *Follower) Next() {
+	follower.row++
+}
+
+// Go back a row. This stays on the first row if already there.
+func (follower *Follower) Previous() {
+	if follower.row > 0 {
+		follower.row--
+	}
+}
+
+// Go to a row, counting from 1
+func (follower *Follower) GoTo(row int) error {
+	if row < 1 {
+		return fmt.Errorf("row must be a positive integer, got %d", row)
+	}
+
+	follower.row = row - 1
+	return nil
+}
+
+func (follower *Follower) isRightSide() bool {
+	return follower.row%2 == 0
+}
+
+// The chart of one repeat with the current row marked with '>' and the
+// direction it is worked in. Every row is labeled with its row number
+// within the repeat.
+func (follower *Follower) Chart() []string {
+	chart := follower.fabric.HandleReverseRows().Rotate180().ToStrings()
+	current := len(chart) - follower.RepeatRow()
+
+	result := make([]string, len(chart))
+	for i, row := range chart {
+		rowNumber := len(chart) - i
+		if i != current {
+			result[i] = fmt.Sprintf("  %s  %d", row, rowNumber)
+			continue
+		}
+
+		direction := "-->"
+		if follower.isRightSide() {
+			direction = "<--"
+		}
+		result[i] = fmt.Sprintf("> %s  %d %s", row, rowNumber, direction)
+	}
+
+	return result
+}
+
+// Written instructions for the current row
+func (follower *Follower) Instructions() string {
+	side := "WS, -->"
+	if follower.isRightSide() {
+		side = "RS, <--"
+	}
+
+	row := follower.fabric[follower.row%len(follower.fabric)]
+	return fmt.Sprintf(
+		"Row %d (row %d of %d, %s): %s",
+		follower.Row(),
+		follower.RepeatRow(),
+		len(follower.fabric),
+		side,
+		instructions.FormatRow(row),
+	)
+}
+
+const help = "[Enter] next row, p previous row, g N go to row N, q quit"
+
+// Apply a command typed by the knitter. This returns true if the knitter
+// wants to quit.
+func (follower *Follower) HandleCommand(command string) (bool, error) {
+	fields := strings.Fields(strings.ToLower(command))
+	if len(fields) == 0 {
+		follower.Next()
+		return false, nil
+	}
+
+	switch fields[0] {
+	case "n", "next":
+		follower.Next()
+	case "p", "prev", "previous":
+		follower.Previous()
+	case "g", "goto":
+		if len(fields) < 2 {
+			return false, errors.New("usage: g ROW")
+		}
+
+		row, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return false, fmt.Errorf("row must be a positive integer, got %s", fields[1])
+		}
+
+		return false, follower.GoTo(row)
+	case "q", "quit":
+		return true, nil
+	default:
+		return false, fmt.Errorf("unknown command %s. %s", fields[0], help)
+	}
+
+	return false, nil
+}
+
+func (follower *Follower) print(out io.Writer) {
+	for _, line := range follower.Chart() {
+		fmt.Fprintln(out, line)
+	}
+	fmt.Fprintln(out, follower.Instructions())
+	fmt.Fprintln(out, help)
+}
+
+// Run the interactive mode, reading commands from in one line at a time
+// until the knitter quits or the input ends. save is called with the
+// current row (counting from 1) every time it changes.
+func Run(in io.Reader, out io.Writer, follower *Follower, save func(row int) error) error {
+	follower.print(out)
+
+	scanner := bufio.NewScanner(in)
+	for scanner.Scan() {
+		before := follower.Row()
+		quit, err := follower.HandleCommand(scanner.Text())
+		if err != nil {
+			fmt.Fprintln(out, err)
+			continue
+		}
+
+		if quit {
+			return nil
+		}
+
+		if follower.Row() != before {
+			if err := save(follower.Row()); err != nil {
+				return err
+			}
+		}
+
+		follower.print(out)
+	}
+
+	return scanner.Err()
+}
@@ -0,0 +1,211 @@
+package follow
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ptrgags/mindless-stitchcraft/checks"
+	"github.com/ptrgags/mindless-stitchcraft/knitting"
+)
+
+func makeFabric() knitting.Fabric {
+	return knitting.Fabric{
+		{knitting.Knit, knitting.Purl, knitting.Purl},
+		{knitting.Knit, knitting.Knit, knitting.Purl},
+	}
+}
+
+func TestState(t *testing.T) {
+	t.Run("missing file returns ErrNotExist", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "missing.json")
+
+		result, err := LoadState(path)
+
+		if !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("expected ErrNotExist, got (%v, %v)", result, err)
+		}
+	})
+
+	t.Run("invalid file returns error", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "state.json")
+		_ = os.WriteFile(path, []byte("not json"), 0644)
+
+		result, err := LoadState(path)
+
+		checks.CheckHasError(t, result, err, "invalid state file")
+	})
+
+	t.Run("saved state can be loaded", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "state.json")
+		state := State{"sync", 10, []string{"v--", "vv-"}, 12}
+
+		err := SaveState(path, state)
+		checks.CheckHasNoError(t, state, err)
+		result, err := LoadState(path)
+
+		checks.CheckHasNoError(t, result, err)
+		if !result.SamePattern(state) || result.Row != 12 {
+			t.Errorf("expected %v, got %v", state, result)
+		}
+	})
+
+	t.Run("different motifs are a different pattern", func(t *testing.T) {
+		a := State{"zigzag", 10, []string{"v--"}, 1}
+		b := State{"zigzag", 10, []string{"vv-"}, 1}
+
+		if a.SamePattern(b) {
+			t.Errorf("expected %v and %v to be different patterns", a, b)
+		}
+	})
+}
+
+func TestFollower(t *testing.T) {
+	t.Run("empty fabric returns error", func(t *testing.T) {
+		result, err := NewFollower(knitting.Fabric{}, 1)
+
+		checks.CheckHasError(t, result, err, "fabric must not be empty")
+	})
+
+	t.Run("invalid starting row returns error", func(t *testing.T) {
+		result, err := NewFollower(makeFabric(), 0)
+
+		checks.CheckHasError(t, result, err, "row must be a positive integer, got 0")
+	})
+
+	t.Run("marks the current row in the chart", func(t *testing.T) {
+		follower, _ := NewFollower(makeFabric(), 1)
+
+		result := follower.Chart()
+
+		expected := []string{
+			"  --v  2",
+			"> --v  1 <--",
+		}
+		checks.CheckSlicesEqual(t, result, expected)
+	})
+
+	t.Run("wrong side rows are worked left to right", func(t *testing.T) {
+		follower, _ := NewFollower(makeFabric(), 2)
+
+		result := follower.Chart()
+
+		expected := []string{
+			"> --v  2 -->",
+			"  --v  1",
+		}
+		checks.CheckSlicesEqual(t, result, expected)
+		if follower.Instructions() != "Row 2 (row 2 of 2, WS, -->): k2, p1" {
+			t.Errorf("unexpected instructions %s", follower.Instructions())
+		}
+	})
+
+	t.Run("rows keep counting past the end of the repeat", func(t *testing.T) {
+		follower, _ := NewFollower(makeFabric(), 2)
+
+		follower.Next()
+
+		if follower.Row() != 3 || follower.RepeatRow() != 1 {
+			t.Errorf("expected row 3 (1 of repeat), got row %d (%d of repeat)", follower.Row(), follower.RepeatRow())
+		}
+		if follower.Instructions() != "Row 3 (row 1 of 2, RS, <--): k1, p2" {
+			t.Errorf("unexpected instructions %s", follower.Instructions())
+		}
+	})
+
+	t.Run("previous stops at the first row", func(t *testing.T) {
+		follower, _ := NewFollower(makeFabric(), 1)
+
+		follower.Previous()
+
+		if follower.Row() != 1 {
+			t.Errorf("expected row 1, got %d", follower.Row())
+		}
+	})
+}
+
+func TestHandleCommand(t *testing.T) {
+	cases := []struct {
+		command  string
+		expected int
+	}{
+		{"", 4},
+		{"n", 4},
+		{"p", 2},
+		{"g 7", 7},
+		{"G 1", 1},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.command, func(t *testing.T) {
+			follower, _ := NewFollower(makeFabric(), 3)
+
+			quit, err := follower.HandleCommand(tc.command)
+
+			checks.CheckHasNoError(t, quit, err)
+			if quit || follower.Row() != tc.expected {
+				t.Errorf("expected row %d, got %d (quit = %v)", tc.expected, follower.Row(), quit)
+			}
+		})
+	}
+
+	t.Run("q quits", func(t *testing.T) {
+		follower, _ := NewFollower(makeFabric(), 3)
+
+		quit, err := follower.HandleCommand("q")
+
+		checks.CheckHasNoError(t, quit, err)
+		if !quit {
+			t.Errorf("expected q to quit")
+		}
+	})
+
+	t.Run("invalid row returns error", func(t *testing.T) {
+		follower, _ := NewFollower(makeFabric(), 3)
+
+		quit, err := follower.HandleCommand("g x")
+
+		checks.CheckHasError(t, quit, err, "row must be a positive integer, got x")
+	})
+
+	t.Run("unknown command returns error", func(t *testing.T) {
+		follower, _ := NewFollower(makeFabric(), 3)
+
+		quit, err := follower.HandleCommand("jump")
+
+		checks.CheckHasError(t, quit, err, "unknown command jump")
+	})
+}
+
+func TestRun(t *testing.T) {
+	t.Run("saves each row change until quitting", func(t *testing.T) {
+		follower, _ := NewFollower(makeFabric(), 1)
+		in := strings.NewReader("\n\nbad\np\nq\nn\n")
+		out := &strings.Builder{}
+		saved := []int{}
+
+		err := Run(in, out, follower, func(row int) error {
+			saved = append(saved, row)
+			return nil
+		})
+
+		checks.CheckHasNoError(t, saved, err)
+		checks.CheckSlicesEqual(t, saved, []int{2, 3, 2})
+		if !strings.Contains(out.String(), "unknown command bad") {
+			t.Errorf("expected an error message in the output, got %s", out.String())
+		}
+	})
+
+	t.Run("save errors stop the loop", func(t *testing.T) {
+		follower, _ := NewFollower(makeFabric(), 1)
+		in := strings.NewReader("\n\n")
+
+		err := Run(in, &strings.Builder{}, follower, func(row int) error {
+			return errors.New("disk full")
+		})
+
+		checks.CheckHasError(t, nil, err, "disk full")
+	})
+}
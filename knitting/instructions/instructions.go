@@ -0,0 +1,190 @@
+package instructions
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ptrgags/mindless-stitchcraft/knitting"
+)
+
+// A run of identical stitches, e.g. "k3"
+type run struct {
+	stitch knitting.KnitStitch
+	count  int
+}
+
+func (r run) String() string {
+	return r.stitch.WriteRun(r.count)
+}
+
+// Run-length encode a row of stitches
+func encodeRuns(stitches knitting.Row) []run {
+	result := []run{}
+	for _, stitch := range stitches {
+		last := len(result) - 1
+		if last >= 0 && result[last].stitch == stitch {
+			result[last].count++
+		} else {
+			result = append(result, run{stitch, 1})
+		}
+	}
+
+	return result
+}
+
+func joinRuns(runs []run) string {
+	parts := make([]string, len(runs))
+	for i, r := range runs {
+		parts[i] = r.String()
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// Count how many times the block row[start:start+length] repeats back to
+// back starting at start.
+func countRepeats(row knitting.Row, start int, length int) int {
+	repeats := 1
+	for {
+		next := start + (repeats+1)*length
+		if next > len(row) {
+			return repeats
+		}
+
+		for i := next - length; i < next; i++ {
+			if row[i] != row[start+(i-start)%length] {
+				return repeats
+			}
+		}
+
+		repeats++
+	}
+}
+
+// A row split into a prefix, a block that is repeated, and whatever
+// stitches remain at the end.
+type repeatedBlock struct {
+	prefix  []run
+	block   []run
+	suffix  []run
+	repeats int
+	// How many stitches are left after the repeats
+	remaining int
+}
+
+// The number of instructions a knitter has to remember
+func (r repeatedBlock) cost() int {
+	return len(r.prefix) + len(r.block) + len(r.suffix) + 1
+}
+
+// Search for the repeated block that makes the shortest instructions.
+// This returns false if repeating a block is no shorter than listing every
+// run of stitches.
+func findRepeatedBlock(row knitting.Row) (repeatedBlock, bool) {
+	n := len(row)
+	bestCost := len(encodeRuns(row))
+	best := repeatedBlock{}
+	found := false
+
+	for start := 0; start < n; start++ {
+		for length := 1; start+2*length <= n; length++ {
+			repeats := countRepeats(row, start, length)
+			if repeats < 2 {
+				continue
+			}
+
+			end := start + repeats*length
+			candidate := repeatedBlock{
+				prefix:    encodeRuns(row[:start]),
+				block:     encodeRuns(row[start : start+length]),
+				suffix:    encodeRuns(row[end:]),
+				repeats:   repeats,
+				remaining: n - end,
+			}
+
+			if candidate.cost() < bestCost {
+				bestCost = candidate.cost()
+				best = candidate
+				found = true
+			}
+		}
+	}
+
+	return best, found
+}
+
+// Write a single row as standard knitting abbreviations in the order the
+// stitches are worked. Runs of the same stitch are combined (e.g. "k2, p3")
+// and a repeated block of stitches is written like
+// "k1, *k2, p1; rep from * to last 2 sts, k2".
+func FormatRow(row knitting.Row) string {
+	block, found := findRepeatedBlock(row)
+	if !found {
+		return joinRuns(encodeRuns(row))
+	}
+
+	parts := []string{}
+	if len(block.prefix) > 0 {
+		parts = append(parts, joinRuns(block.prefix))
+	}
+
+	until := "end"
+	if block.remaining == 1 {
+		until = "last st"
+	} else if block.remaining > 1 {
+		until = fmt.Sprintf("last %d sts", block.remaining)
+	}
+	parts = append(parts, fmt.Sprintf("*%s; rep from * to %s", joinRuns(block.block), until))
+
+	if len(block.suffix) > 0 {
+		parts = append(parts, joinRuns(block.suffix))
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// Write instructions for flat knitting. The fabric must be listed in
+// stitching order, i.e. the first row worked comes first, and each row
+// lists stitches in the order the knitter works them. Odd rows are worked
+// on the right side (RS) from right to left, even rows are worked on the
+// wrong side (WS) from left to right.
+func FormatFlat(fabric knitting.Fabric) []string {
+	result := formatRows(fabric, 0)
+	if len(fabric) > 0 {
+		result = append(result, fmt.Sprintf("Repeat rows 1-%d.", len(fabric)))
+	}
+
+	return result
+}
+
+// Write a line for each row, numbering rows after the first offset rows
+func formatRows(fabric knitting.Fabric, offset int) []string {
+	result := make([]string, 0, len(fabric)+1)
+	for i, row := range fabric {
+		rowNumber := offset + i + 1
+		label := "RS, <--"
+		if rowNumber%2 == 0 {
+			label = "WS, -->"
+		}
+
+		result = append(result, fmt.Sprintf("Row %d (%s): %s", rowNumber, label, FormatRow(row)))
+	}
+
+	return result
+}
+
+// Write instructions for flat knitting with bands of bandRows rows at the
+// bottom and top of the fabric, like FormatFlat. The rows between the
+// bands are repeated to the desired length before working the top band.
+func FormatFlatWithBands(fabric knitting.Fabric, bandRows int) []string {
+	if bandRows == 0 || len(fabric) <= 2*bandRows {
+		return FormatFlat(fabric)
+	}
+
+	bodyEnd := len(fabric) - bandRows
+	result := formatRows(fabric[:bodyEnd], 0)
+	result = append(result, fmt.Sprintf("Repeat rows %d-%d to the desired length, ending after row %d.", bandRows+1, bodyEnd, bodyEnd))
+	result = append(result, formatRows(fabric[bodyEnd:], bodyEnd)...)
+
+	return result
+}
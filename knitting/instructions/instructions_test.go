@@ -0,0 +1,103 @@
+package instructions
+
+import (
+	"testing"
+
+	"github.com/ptrgags/mindless-stitchcraft/checks"
+	"github.com/ptrgags/mindless-stitchcraft/knitting"
+)
+
+func parseRow(stitches string) knitting.Row {
+	motif, _ := knitting.ParseMotif(stitches)
+	return knitting.Row(motif)
+}
+
+func TestFormatRow(t *testing.T) {
+	cases := []struct {
+		label    string
+		row      string
+		expected string
+	}{
+		{"single stitch", "v", "k1"},
+		{"runs are combined", "vv---", "k2, p3"},
+		{"short row without repeats", "vv-vv", "k2, p1, k2"},
+		{"block repeats to the end", "vv-vv-vv-", "*k2, p1; rep from * to end"},
+		{"block repeats to last stitches", "vv-vv-vv-vv", "*k2, p1; rep from * to last 2 sts, k2"},
+		{"block repeats to last stitch", "-vv-vv-", "*p1, k2; rep from * to last st, p1"},
+		{"prefix before the repeat", "---vv-vv-vv-", "p3, *k2, p1; rep from * to end"},
+		{"whole row of one stitch", "vvvvvv", "k6"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.label, func(t *testing.T) {
+			result := FormatRow(parseRow(tc.row))
+
+			if result != tc.expected {
+				t.Errorf("Expected %q, got %q", tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestFormatFlat(t *testing.T) {
+	t.Run("empty fabric returns no instructions", func(t *testing.T) {
+		result := FormatFlat(knitting.Fabric{})
+
+		checks.CheckSliceEmpty(t, result)
+	})
+
+	t.Run("rows alternate between right and wrong side", func(t *testing.T) {
+		fabric := knitting.Fabric{
+			parseRow("v--v"),
+			parseRow("--vv"),
+			parseRow("vvvv"),
+		}
+
+		result := FormatFlat(fabric)
+
+		expected := []string{
+			"Row 1 (RS, <--): k1, p2, k1",
+			"Row 2 (WS, -->): p2, k2",
+			"Row 3 (RS, <--): k4",
+			"Repeat rows 1-3.",
+		}
+		checks.CheckSlicesEqual(t, result, expected)
+	})
+}
+
+func TestFormatFlatWithBands(t *testing.T) {
+	t.Run("no bands is the same as FormatFlat", func(t *testing.T) {
+		fabric := knitting.Fabric{
+			parseRow("v--v"),
+			parseRow("--vv"),
+		}
+
+		result := FormatFlatWithBands(fabric, 0)
+
+		checks.CheckSlicesEqual(t, result, FormatFlat(fabric))
+	})
+
+	t.Run("only the rows between the bands are repeated", func(t *testing.T) {
+		fabric := knitting.Fabric{
+			parseRow("vvvv"),
+			parseRow("vvvv"),
+			parseRow("v--v"),
+			parseRow("--vv"),
+			parseRow("vvvv"),
+			parseRow("vvvv"),
+		}
+
+		result := FormatFlatWithBands(fabric, 2)
+
+		expected := []string{
+			"Row 1 (RS, <--): k4",
+			"Row 2 (WS, -->): k4",
+			"Row 3 (RS, <--): k1, p2, k1",
+			"Row 4 (WS, -->): p2, k2",
+			"Repeat rows 3-4 to the desired length, ending after row 4.",
+			"Row 5 (RS, <--): k4",
+			"Row 6 (WS, -->): k4",
+		}
+		checks.CheckSlicesEqual(t, result, expected)
+	})
+}
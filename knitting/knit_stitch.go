@@ -1,38 +1,166 @@
 package knitting
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 type KnitStitch int
 
 const (
 	Knit KnitStitch = iota
 	Purl
+	// Knit through the back loop (k1 tbl)
+	KnitTwisted
+	// Purl through the back loop (p1 tbl)
+	PurlTwisted
+	// Slip a stitch purlwise with the yarn held in back
+	SlipWyib
+	// Slip a stitch purlwise with the yarn held in front
+	SlipWyif
+	YarnOver
+	// Knit two together, a right-leaning decrease
+	K2tog
+	// Purl two together
+	P2tog
+	// Slip, slip, knit, a left-leaning decrease
+	Ssk
+	// Slip, slip, purl, the wrong side equivalent of ssk
+	Ssp
+	// Slip one, knit two together, pass the slipped stitch over. A
+	// left-leaning double decrease.
+	Sk2p
+	// Slip three, purl three together through the back loops, the wrong
+	// side equivalent of sk2p
+	Sssp
 )
 
+// Properties of each type of stitch
+type stitchInfo struct {
+	symbol rune
+	// The stitch that looks the same from the other side of the fabric.
+	// E.g. a knit stitch looks like a purl from the back.
+	wrongSide KnitStitch
+	// How many stitches are worked from the left needle
+	consumes int
+	// How many stitches end up on the right needle
+	produces int
+	// Abbreviation in written instructions. If counted, this is a format
+	// string for the number of stitches, e.g. "k%d" for "k2"
+	abbreviation string
+	counted      bool
+}
+
+var stitchTable = map[KnitStitch]stitchInfo{
+	Knit:        {'v', Purl, 1, 1, "k%d", true},
+	Purl:        {'-', Knit, 1, 1, "p%d", true},
+	KnitTwisted: {'b', PurlTwisted, 1, 1, "k%d tbl", true},
+	PurlTwisted: {'q', KnitTwisted, 1, 1, "p%d tbl", true},
+	// When slipping on the wrong side, the yarn in back ends up on the
+	// right side of the fabric
+	SlipWyib: {'s', SlipWyif, 1, 1, "sl%d wyib", true},
+	SlipWyif: {'f', SlipWyib, 1, 1, "sl%d wyif", true},
+	YarnOver: {'o', YarnOver, 0, 1, "yo", false},
+	K2tog:    {'/', P2tog, 2, 1, "k2tog", false},
+	P2tog:    {'z', K2tog, 2, 1, "p2tog", false},
+	Ssk:      {'\\', Ssp, 2, 1, "ssk", false},
+	Ssp:      {'n', Ssk, 2, 1, "ssp", false},
+	Sk2p:     {'^', Sssp, 3, 1, "sk2p", false},
+	Sssp:     {'m', Sk2p, 3, 1, "sssp", false},
+}
+
+// All stitch types, in the order they are declared
+func AllStitches() []KnitStitch {
+	return []KnitStitch{
+		Knit,
+		Purl,
+		KnitTwisted,
+		PurlTwisted,
+		SlipWyib,
+		SlipWyif,
+		YarnOver,
+		K2tog,
+		P2tog,
+		Ssk,
+		Ssp,
+		Sk2p,
+		Sssp,
+	}
+}
+
 func (stitch KnitStitch) ToRune() rune {
-	if stitch == Knit {
-		return 'v'
+	if info, ok := stitchTable[stitch]; ok {
+		return info.symbol
+	}
+
+	return '?'
+}
+
+// Write a run of count copies of this stitch as it would appear in written
+// instructions, e.g. "k2", "sl1 wyif" or "yo, yo" for stitches that are
+// not usually counted.
+func (stitch KnitStitch) WriteRun(count int) string {
+	info, ok := stitchTable[stitch]
+	if !ok {
+		return "?"
 	}
 
-	return '-'
+	if info.counted {
+		return fmt.Sprintf(info.abbreviation, count)
+	}
+
+	words := make([]string, count)
+	for i := range words {
+		words[i] = info.abbreviation
+	}
+	return strings.Join(words, ", ")
 }
 
+// Get the stitch that is worked on the wrong side of the fabric to
+// produce the same result on the right side (and vice-versa). For example,
+// a knit stitch on the wrong side looks like a purl from the front.
 func (stitch KnitStitch) Swap() KnitStitch {
-	if stitch == Knit {
-		return Purl
+	if info, ok := stitchTable[stitch]; ok {
+		return info.wrongSide
 	}
 
-	return Knit
+	return stitch
 }
 
-func ParseKnitStitch(stitch rune) (KnitStitch, error) {
-	if stitch == 'v' {
-		return Knit, nil
+// How many stitches are worked off the left needle for this stitch.
+// E.g. k2tog works 2 stitches and a yarn over works none.
+func (stitch KnitStitch) StitchesConsumed() int {
+	return stitchTable[stitch].consumes
+}
+
+// How many stitches end up on the right needle for this stitch.
+func (stitch KnitStitch) StitchesProduced() int {
+	return stitchTable[stitch].produces
+}
+
+// How much this stitch changes the width of the row. E.g. a yarn over
+// adds a stitch (+1) and k2tog removes one (-1)
+func (stitch KnitStitch) StitchCountChange() int {
+	return stitch.StitchesProduced() - stitch.StitchesConsumed()
+}
+
+func symbolList() string {
+	symbols := []string{}
+	for _, stitch := range AllStitches() {
+		if stitch == Knit || stitch == Purl {
+			continue
+		}
+		symbols = append(symbols, string(stitch.ToRune()))
 	}
+	return strings.Join(symbols, " ")
+}
 
-	if stitch == '-' {
-		return Purl, nil
+func ParseKnitStitch(stitch rune) (KnitStitch, error) {
+	for _, candidate := range AllStitches() {
+		if candidate.ToRune() == stitch {
+			return candidate, nil
+		}
 	}
 
-	return Knit, fmt.Errorf("stitch %s must be a knit (v) or purl (-)", string(stitch))
+	return Knit, fmt.Errorf("stitch %s must be a knit (v) or purl (-) or one of the other stitch symbols (%s)", string(stitch), symbolList())
 }
@@ -24,6 +24,32 @@ func TestKnitStitchToRune(t *testing.T) {
 	})
 }
 
+func TestKnitStitchWriteRun(t *testing.T) {
+	cases := []struct {
+		label    string
+		stitch   KnitStitch
+		count    int
+		expected string
+	}{
+		{"knits are counted", Knit, 2, "k2"},
+		{"purls are counted", Purl, 1, "p1"},
+		{"twisted stitches are counted", KnitTwisted, 3, "k3 tbl"},
+		{"slipped stitches are counted", SlipWyif, 2, "sl2 wyif"},
+		{"yarn overs are listed individually", YarnOver, 2, "yo, yo"},
+		{"decreases are listed individually", K2tog, 1, "k2tog"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.label, func(t *testing.T) {
+			result := tc.stitch.WriteRun(tc.count)
+
+			if result != tc.expected {
+				t.Errorf("Expected %v, got %v", tc.expected, result)
+			}
+		})
+	}
+}
+
 func TestKnitStitchSwap(t *testing.T) {
 	t.Run("Returns Purl for Knit", func(t *testing.T) {
 		result := Knit.Swap()
@@ -40,6 +66,69 @@ func TestKnitStitchSwap(t *testing.T) {
 			t.Errorf("Expected Knit, got %v", result)
 		}
 	})
+
+	t.Run("Returns the wrong side equivalent of other stitches", func(t *testing.T) {
+		cases := []struct {
+			stitch   KnitStitch
+			expected KnitStitch
+		}{
+			{KnitTwisted, PurlTwisted},
+			{PurlTwisted, KnitTwisted},
+			{SlipWyib, SlipWyif},
+			{SlipWyif, SlipWyib},
+			{YarnOver, YarnOver},
+			{K2tog, P2tog},
+			{P2tog, K2tog},
+			{Ssk, Ssp},
+			{Ssp, Ssk},
+			{Sk2p, Sssp},
+			{Sssp, Sk2p},
+		}
+
+		for _, tc := range cases {
+			result := tc.stitch.Swap()
+
+			if result != tc.expected {
+				t.Errorf("Expected %v, got %v", tc.expected, result)
+			}
+		}
+	})
+
+	t.Run("Swapping twice returns the same stitch", func(t *testing.T) {
+		for _, stitch := range AllStitches() {
+			result := stitch.Swap().Swap()
+
+			if result != stitch {
+				t.Errorf("Expected %v, got %v", stitch, result)
+			}
+		}
+	})
+}
+
+func TestKnitStitchCountChange(t *testing.T) {
+	cases := []struct {
+		label    string
+		stitch   KnitStitch
+		expected int
+	}{
+		{"knit keeps the count", Knit, 0},
+		{"slip keeps the count", SlipWyib, 0},
+		{"yarn over adds a stitch", YarnOver, 1},
+		{"k2tog removes a stitch", K2tog, -1},
+		{"p2tog removes a stitch", P2tog, -1},
+		{"ssk removes a stitch", Ssk, -1},
+		{"sk2p removes two stitches", Sk2p, -2},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.label, func(t *testing.T) {
+			result := tc.stitch.StitchCountChange()
+
+			if result != tc.expected {
+				t.Errorf("Expected %v, got %v", tc.expected, result)
+			}
+		})
+	}
 }
 
 func TestParseKnitStitch(t *testing.T) {
@@ -58,6 +147,17 @@ func TestParseKnitStitch(t *testing.T) {
 		}
 	})
 
+	t.Run("parses every stitch symbol", func(t *testing.T) {
+		for _, stitch := range AllStitches() {
+			result, err := ParseKnitStitch(stitch.ToRune())
+
+			checks.CheckHasNoError(t, result, err)
+			if result != stitch {
+				t.Errorf("Expected %v, got %v", stitch, result)
+			}
+		}
+	})
+
 	t.Run("- results in Purl", func(t *testing.T) {
 		result, err := ParseKnitStitch('-')
 
@@ -0,0 +1,261 @@
+package lace
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ptrgags/mindless-stitchcraft/knitting"
+	"github.com/ptrgags/mindless-stitchcraft/knitting/instructions"
+)
+
+// Give up if the pattern has not repeated after this many rows
+const maxRows = 10000
+
+// A lace pattern. Yarn overs and decreases may be spread over several
+// rows, so rows do not always have the same number of stitches.
+type Pattern struct {
+	// Rows in stitching order, each listed in the order the stitches are
+	// worked
+	Rows knitting.Fabric
+	// How many stitches are on the needle at the start of each row. The
+	// pattern repeats, so the last row leaves as many stitches as the
+	// first repeated row starts with.
+	Widths []int
+	// Index of the first row of the repeat. Rows before it are set up rows
+	// that are only worked once, which happens when the rows settle into a
+	// different stitch count than the cast on.
+	RepeatStart int
+}
+
+// How many stitches are on the needle at the end of a row
+func (pattern Pattern) EndWidth(row int) int {
+	return pattern.Widths[row] + pattern.NetChange(row)
+}
+
+// How many stitches a row (counting from 0) adds or removes
+func (pattern Pattern) NetChange(row int) int {
+	return pattern.Rows[row].StitchesProduced() - pattern.Rows[row].StitchesConsumed()
+}
+
+// The total stitch count change of one repeat of the motif
+func NetChange(motif knitting.Motif) int {
+	return knitting.Row(motif).StitchesProduced() - knitting.Row(motif).StitchesConsumed()
+}
+
+func checkMotifs(motifs []knitting.Motif) error {
+	if len(motifs) < 1 {
+		return errors.New("motifs must be non-empty")
+	}
+
+	for i, motif := range motifs {
+		if len(motif) == 0 {
+			return fmt.Errorf("motif %d must not be empty", i+1)
+		}
+
+		if change := NetChange(motif); change != 0 {
+			return fmt.Errorf("motif %d changes the stitch count by %+d stitch(es) per repeat, so the fabric would keep growing or shrinking", i+1, change)
+		}
+	}
+
+	return nil
+}
+
+// Everything that determines how the rest of the pattern is generated
+type state struct {
+	motif    int
+	position int
+	width    int
+	side     int
+	// A decrease that straddled the end of the previous row, which starts
+	// this row. Only used by sync patterns, zigzag patterns just continue
+	// reading the motif from the decrease.
+	carried  knitting.KnitStitch
+	carrying bool
+}
+
+// A stitch that works more stitches than the whole row has can't be carried
+// over either
+func tooWide(stitch knitting.KnitStitch, width int) error {
+	return fmt.Errorf("%s works %d stitches but the row only has %d. Try a wider fabric", stitch.WriteRun(1), stitch.StitchesConsumed(), width)
+}
+
+// Fill a row that works all the stitches on the needle, reading the motif
+// from the current position. A decrease carried over from the previous row
+// is worked first.
+//
+// If a decrease would straddle the end of the row, the stitches that are
+// left are worked plain and the decrease is carried over: it starts the
+// next row and works those stitches together with the rest, just like the
+// motif itself continues from row to row. This returns the row, where to
+// continue reading the motif (which is the carried decrease itself if
+// there is one), and the decrease to carry over.
+func fillRow(motif knitting.Motif, current state) (knitting.Row, int, *knitting.KnitStitch, error) {
+	row := knitting.Row{}
+	position := current.position
+	consumed := 0
+	if current.carrying {
+		if current.carried.StitchesConsumed() > current.width {
+			return nil, 0, nil, tooWide(current.carried, current.width)
+		}
+
+		row = append(row, current.carried)
+		consumed += current.carried.StitchesConsumed()
+	}
+
+	for consumed < current.width {
+		stitch := motif[position]
+		if consumed+stitch.StitchesConsumed() > current.width {
+			if stitch.StitchesConsumed() > current.width {
+				return nil, 0, nil, tooWide(stitch, current.width)
+			}
+
+			for ; consumed < current.width; consumed++ {
+				row = append(row, knitting.Knit)
+			}
+			return row, position, &stitch, nil
+		}
+
+		row = append(row, stitch)
+		consumed += stitch.StitchesConsumed()
+		position = (position + 1) % len(motif)
+	}
+
+	return row, position, nil, nil
+}
+
+func generate(width int, motifs []knitting.Motif, restart bool) (Pattern, error) {
+	if width < 1 {
+		return Pattern{}, errors.New("fabricWidth must be a positive integer")
+	}
+
+	if err := checkMotifs(motifs); err != nil {
+		return Pattern{}, err
+	}
+
+	pattern := Pattern{}
+	seen := map[state]int{}
+	current := state{width: width}
+	for row := 0; ; row++ {
+		if first, ok := seen[current]; ok {
+			pattern.RepeatStart = first
+			break
+		}
+		seen[current] = row
+
+		if row == maxRows {
+			return Pattern{}, fmt.Errorf("the pattern does not repeat within %d rows", maxRows)
+		}
+
+		motif := motifs[current.motif]
+		stitches, position, carried, err := fillRow(motif, current)
+		if err != nil {
+			return Pattern{}, fmt.Errorf("row %d: %w", row+1, err)
+		}
+		pattern.Rows = append(pattern.Rows, stitches)
+		pattern.Widths = append(pattern.Widths, current.width)
+
+		next := state{
+			motif:    current.motif,
+			position: position,
+			width:    pattern.EndWidth(row),
+			side:     (row + 1) % 2,
+		}
+		if restart {
+			// The next motif starts from the beginning, so the carried
+			// decrease has to be remembered separately
+			next.motif = (current.motif + 1) % len(motifs)
+			next.position = 0
+			if carried != nil {
+				next.carried = *carried
+				next.carrying = true
+			}
+		}
+
+		if next.width < 1 {
+			return Pattern{}, fmt.Errorf("row %d decreases away every stitch", row+1)
+		}
+		current = next
+	}
+
+	return pattern, nil
+}
+
+// Repeat the motif continuously from row to row, like knit-zigzag. Each
+// row works exactly the stitches on the needle, so the number of motif
+// stitches in a row depends on how many stitches they work. A decrease that
+// would straddle the end of a row is carried over to the start of the next
+// row, see fillRow.
+//
+// The motif must not change the stitch count overall, but a single row may.
+// Carrying a decrease over can leave the rows with a different number of
+// stitches than the cast on, so the pattern may start with set up rows
+// before the repeat.
+func GenerateZigzag(fabricWidth int, motif knitting.Motif) (Pattern, error) {
+	return generate(fabricWidth, []knitting.Motif{motif}, false)
+}
+
+// Start each row at the beginning of the next motif, like knit-sync. A
+// decrease that would straddle the end of a row is worked at the start of
+// the next row before the next motif begins.
+func GenerateSync(fabricWidth int, motifs []knitting.Motif) (Pattern, error) {
+	return generate(fabricWidth, motifs, true)
+}
+
+// The chart of the right side of the fabric with the first row at the
+// bottom. Rows with fewer stitches are padded on the left with spaces so
+// the right edges line up.
+func (pattern Pattern) Chart() []string {
+	chart := pattern.Rows.HandleReverseRows().Rotate180().ToStrings()
+
+	widest := 0
+	for _, row := range pattern.Rows {
+		widest = max(widest, len(row))
+	}
+
+	for i, row := range chart {
+		chart[i] = fmt.Sprintf("%*s", widest, row)
+	}
+
+	return chart
+}
+
+// Write instructions for each row, followed by how many stitches are on
+// the needle at the end of the row and how much it changed.
+func (pattern Pattern) Instructions() []string {
+	result := make([]string, 0, len(pattern.Rows)+1)
+	for i, row := range pattern.Rows {
+		label := "RS, <--"
+		if i%2 == 1 {
+			label = "WS, -->"
+		}
+
+		result = append(result, fmt.Sprintf(
+			"Row %d (%s): %s. %d sts (%+d)",
+			i+1,
+			label,
+			instructions.FormatRow(row),
+			pattern.EndWidth(i),
+			pattern.NetChange(i),
+		))
+	}
+	result = append(result, fmt.Sprintf("Repeat rows %d-%d.", pattern.RepeatStart+1, len(pattern.Rows)))
+
+	return result
+}
+
+// Summarize the stitch count changes, e.g. "+1 -1". Rows that keep the
+// stitch count are written as "0".
+func (pattern Pattern) DescribeChanges() string {
+	changes := make([]string, len(pattern.Rows))
+	for i := range pattern.Rows {
+		change := pattern.NetChange(i)
+		if change == 0 {
+			changes[i] = "0"
+		} else {
+			changes[i] = fmt.Sprintf("%+d", change)
+		}
+	}
+
+	return strings.Join(changes, " ")
+}
@@ -0,0 +1,174 @@
+package lace
+
+import (
+	"testing"
+
+	"github.com/ptrgags/mindless-stitchcraft/checks"
+	"github.com/ptrgags/mindless-stitchcraft/knitting"
+)
+
+func TestNetChange(t *testing.T) {
+	motif, _ := knitting.ParseAnyMotif("k1 yo k1 yo sk2p")
+
+	result := NetChange(motif)
+
+	if result != 0 {
+		t.Errorf("expected 0, got %d", result)
+	}
+}
+
+func TestGenerateZigzag(t *testing.T) {
+	t.Run("invalid width returns error", func(t *testing.T) {
+		motif, _ := knitting.ParseAnyMotif("yo k2tog")
+
+		result, err := GenerateZigzag(0, motif)
+
+		checks.CheckHasError(t, result, err, "fabricWidth must be a positive integer")
+	})
+
+	t.Run("unbalanced motif returns error", func(t *testing.T) {
+		motif, _ := knitting.ParseAnyMotif("k1 yo k1")
+
+		result, err := GenerateZigzag(4, motif)
+
+		checks.CheckHasError(t, result, err, "motif 1 changes the stitch count by +1 stitch(es) per repeat, so the fabric would keep growing or shrinking")
+	})
+
+	t.Run("balanced rows keep the same width", func(t *testing.T) {
+		motif, _ := knitting.ParseAnyMotif("k1 yo k2tog")
+
+		result, err := GenerateZigzag(6, motif)
+
+		checks.CheckHasNoError(t, result, err)
+		checks.CheckSlicesEqual(t, result.Rows.ToStrings(), []string{"vo/vo/", "vo/vo/"})
+		checks.CheckSlicesEqual(t, result.Widths, []int{6, 6})
+	})
+
+	t.Run("rows may change the stitch count", func(t *testing.T) {
+		motif, _ := knitting.ParseAnyMotif("k1 yo k1 yo k1 sk2p")
+
+		result, err := GenerateZigzag(3, motif)
+
+		checks.CheckHasNoError(t, result, err)
+		checks.CheckSlicesEqual(t, result.Rows.ToStrings(), []string{"vovov", "^vov", "ov^", "vovov", "^vov", "ov^"})
+		checks.CheckSlicesEqual(t, result.Widths, []int{3, 5, 4, 3, 5, 4})
+		if result.DescribeChanges() != "+2 -1 -1 +2 -1 -1" {
+			t.Errorf("expected changes +2 -1 -1 +2 -1 -1, got %s", result.DescribeChanges())
+		}
+	})
+
+	t.Run("decrease that straddles a row is carried over to the next row", func(t *testing.T) {
+		cases := []struct {
+			motif    string
+			width    int
+			expected []string
+		}{
+			// Only 1 stitch is left for the k2tog at the end of row 1
+			{"yo k2tog", 5, []string{"o/o/ov", "/o/o/"}},
+			{"k1 yo k1 ssk", 7, []string{"vov\\vovv", "\\vov\\vov", "\\vov\\vov"}},
+			// The sk2p needs 3 stitches but only 2 are left after k1, yo, k1
+			{"k1 yo k1 sk2p k1 yo", 4, []string{"vovvv", "^vov", "ov^", "vovov", "^vov", "ov^", "vovov"}},
+		}
+
+		for _, tc := range cases {
+			t.Run(tc.motif, func(t *testing.T) {
+				motif, _ := knitting.ParseAnyMotif(tc.motif)
+
+				result, err := GenerateZigzag(tc.width, motif)
+
+				checks.CheckHasNoError(t, result, err)
+				checks.CheckSlicesEqual(t, result.Rows.ToStrings(), tc.expected)
+			})
+		}
+	})
+
+	t.Run("stitch count can settle after set up rows", func(t *testing.T) {
+		motif, _ := knitting.ParseAnyMotif("k1 yo k1 sk2p k1 yo")
+
+		result, err := GenerateZigzag(4, motif)
+
+		checks.CheckHasNoError(t, result, err)
+		if result.RepeatStart != 1 || len(result.Rows) != 7 {
+			t.Errorf("expected rows 2-7 to repeat, got rows %d-%d", result.RepeatStart+1, len(result.Rows))
+		}
+	})
+
+	t.Run("decrease wider than the row returns error", func(t *testing.T) {
+		motif, _ := knitting.ParseAnyMotif("yo yo sk2p")
+
+		result, err := GenerateZigzag(2, motif)
+
+		checks.CheckHasError(t, result, err, "row 1: sk2p works 3 stitches but the row only has 2. Try a wider fabric")
+	})
+
+	t.Run("balanced motif keeps the cast on width", func(t *testing.T) {
+		motif, _ := knitting.ParseAnyMotif("k1 yo k1 ssk")
+
+		result, err := GenerateZigzag(8, motif)
+
+		checks.CheckHasNoError(t, result, err)
+		checks.CheckSlicesEqual(t, result.Widths, []int{8, 8})
+		if result.RepeatStart != 0 {
+			t.Errorf("expected no set up rows, got %d", result.RepeatStart)
+		}
+	})
+}
+
+func TestGenerateSync(t *testing.T) {
+	t.Run("each row starts the next motif", func(t *testing.T) {
+		first, _ := knitting.ParseAnyMotif("yo k2tog")
+		second, _ := knitting.ParseAnyMotif("k1")
+
+		result, err := GenerateSync(4, []knitting.Motif{first, second})
+
+		checks.CheckHasNoError(t, result, err)
+		checks.CheckSlicesEqual(t, result.Rows.ToStrings(), []string{"o/o/", "vvvv"})
+		checks.CheckSlicesEqual(t, result.Widths, []int{4, 4})
+	})
+
+	t.Run("decrease that straddles a row starts the next row", func(t *testing.T) {
+		first, _ := knitting.ParseAnyMotif("yo k2tog")
+		second, _ := knitting.ParseAnyMotif("k1")
+
+		result, err := GenerateSync(5, []knitting.Motif{first, second})
+
+		checks.CheckHasNoError(t, result, err)
+		checks.CheckSlicesEqual(t, result.Rows.ToStrings(), []string{"o/o/ov", "/vvvv"})
+		checks.CheckSlicesEqual(t, result.Widths, []int{5, 6})
+	})
+}
+
+func TestChart(t *testing.T) {
+	motif, _ := knitting.ParseAnyMotif("k1 yo k1 yo k1 sk2p")
+	pattern, _ := GenerateZigzag(3, motif)
+
+	result := pattern.Chart()
+
+	expected := []string{
+		"  o-m",
+		" vov^",
+		"-o-o-",
+		"  ^vo",
+		" m-o-",
+		"vovov",
+	}
+	checks.CheckSlicesEqual(t, result, expected)
+}
+
+func TestInstructions(t *testing.T) {
+	motif, _ := knitting.ParseAnyMotif("k1 yo k1 yo k1 sk2p")
+	pattern, _ := GenerateZigzag(3, motif)
+
+	result := pattern.Instructions()
+
+	expected := []string{
+		"Row 1 (RS, <--): *k1, yo; rep from * to last st, k1. 5 sts (+2)",
+		"Row 2 (WS, -->): sk2p, k1, yo, k1. 4 sts (-1)",
+		"Row 3 (RS, <--): yo, k1, sk2p. 3 sts (-1)",
+		"Row 4 (WS, -->): *k1, yo; rep from * to last st, k1. 5 sts (+2)",
+		"Row 5 (RS, <--): sk2p, k1, yo, k1. 4 sts (-1)",
+		"Row 6 (WS, -->): yo, k1, sk2p. 3 sts (-1)",
+		"Repeat rows 1-6.",
+	}
+	checks.CheckSlicesEqual(t, result, expected)
+}
@@ -0,0 +1,143 @@
+package mosaic
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ptrgags/mindless-stitchcraft/knitting"
+	"github.com/ptrgags/mindless-stitchcraft/knitting/phase"
+)
+
+// Slips taller than this many rows pull the fabric too tight
+const MaxSlipHeight = 2
+
+// In a mosaic motif, knit stitches ('v') are knit in the color of the
+// current row pair, and slipped stitches ('s') show the color from the row
+// pair below. Purls ('-') are also read as slips so knit/purl motifs can
+// be reused as they are.
+func isSlip(stitch knitting.KnitStitch) bool {
+	return stitch == knitting.SlipWyib || stitch == knitting.Purl
+}
+
+func checkMotifs(motifs []knitting.Motif) error {
+	for i, motif := range motifs {
+		for _, stitch := range motif {
+			if stitch != knitting.Knit && !isSlip(stitch) {
+				return fmt.Errorf("motif %d must only use knit (v) and slipped (s or -) stitches, got %c", i+1, stitch.ToRune())
+			}
+		}
+	}
+
+	return nil
+}
+
+// Generate a mosaic pattern where the rule decides where each row pair
+// starts in the motifs. Each row of the result is a row pair: the stitches
+// are worked on the right side, then worked again on the way back on the
+// wrong side in the same color. Row pairs alternate between the two
+// colors, and are listed in stitching order as read on the right side.
+func Generate(fabricWidth int, motifs []knitting.Motif, rule phase.Rule) (knitting.Fabric, error) {
+	if err := checkMotifs(motifs); err != nil {
+		return nil, err
+	}
+
+	// The phase engine always returns an even number of rows, so the
+	// colors line up when the pattern repeats
+	rows, _, err := phase.Generate(fabricWidth, motifs, rule)
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+// Compute which color each stitch shows on the front of the fabric, given
+// the two colors of yarn. Even row pairs (counting from 0) are worked in
+// colors[0] and odd row pairs in colors[1]. A slipped stitch shows the
+// color of the stitch below it. The pattern repeats, so the first row pair
+// sits on top of the last one.
+//
+// The result is a chart with one line per row pair, with the first row
+// pair at the bottom.
+func Front(rowPairs knitting.Fabric, colors [2]rune) ([][]rune, error) {
+	if len(rowPairs) == 0 {
+		return nil, errors.New("pattern must not be empty")
+	}
+
+	width := len(rowPairs[0])
+	front := make([][]rune, len(rowPairs))
+	for p := range front {
+		front[p] = make([]rune, width)
+	}
+
+	for i := 0; i < width; i++ {
+		for p := range rowPairs {
+			// Look down the column for the row pair the stitch was knit in
+			below := p
+			for steps := 0; isSlip(rowPairs[below][i]); steps++ {
+				if steps == len(rowPairs) {
+					return nil, fmt.Errorf("column %d is slipped in every row, so it is never knit", i+1)
+				}
+				below = (below + len(rowPairs) - 1) % len(rowPairs)
+			}
+
+			front[p][i] = colors[below%2]
+		}
+	}
+
+	// Stitches are worked right to left, and the first row pair is at the
+	// bottom
+	chart := make([][]rune, len(front))
+	for p, row := range front {
+		reversed := make([]rune, width)
+		for i, color := range row {
+			reversed[width-1-i] = color
+		}
+		chart[len(front)-1-p] = reversed
+	}
+
+	return chart, nil
+}
+
+// A column where a stitch is slipped for too many rows in a row
+type StackedSlip struct {
+	// First row pair where the stitch is slipped, counting from 1
+	RowPair int
+	// Column counting from 1 at the right edge of the chart
+	Column int
+	// How many rows tall the slipped stitch is
+	Height int
+}
+
+// Find every slipped stitch that is taller than MaxSlipHeight rows. Each
+// slip covers both rows of a row pair, so this finds stitches slipped in
+// two or more row pairs in a row. Stacks may wrap around from the last row
+// pair to the first, since the pattern repeats.
+func StackedSlips(rowPairs knitting.Fabric) []StackedSlip {
+	result := []StackedSlip{}
+	if len(rowPairs) == 0 {
+		return result
+	}
+
+	count := len(rowPairs)
+	for p := range rowPairs {
+		for i := range rowPairs[p] {
+			// Only count each stack from where it starts
+			previous := rowPairs[(p+count-1)%count][i]
+			if !isSlip(rowPairs[p][i]) || isSlip(previous) {
+				continue
+			}
+
+			pairs := 0
+			for pairs < count && isSlip(rowPairs[(p+pairs)%count][i]) {
+				pairs++
+			}
+
+			if 2*pairs > MaxSlipHeight {
+				result = append(result, StackedSlip{RowPair: p + 1, Column: i + 1, Height: 2 * pairs})
+			}
+		}
+	}
+
+	return result
+}
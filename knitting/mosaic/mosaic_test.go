@@ -0,0 +1,119 @@
+package mosaic
+
+import (
+	"testing"
+
+	"github.com/ptrgags/mindless-stitchcraft/checks"
+	"github.com/ptrgags/mindless-stitchcraft/knitting"
+	"github.com/ptrgags/mindless-stitchcraft/knitting/phase"
+)
+
+func parseRowPairs(t *testing.T, rows ...string) knitting.Fabric {
+	result := make(knitting.Fabric, len(rows))
+	for i, row := range rows {
+		motif, err := knitting.ParseMotif(row)
+		if err != nil {
+			t.Fatal(err)
+		}
+		result[i] = knitting.Row(motif)
+	}
+
+	return result
+}
+
+func chartStrings(chart [][]rune) []string {
+	result := make([]string, len(chart))
+	for i, row := range chart {
+		result[i] = string(row)
+	}
+
+	return result
+}
+
+func TestGenerate(t *testing.T) {
+	t.Run("stitches other than knits and slips return error", func(t *testing.T) {
+		motif, _ := knitting.ParseMotif("vvo")
+
+		result, err := Generate(4, []knitting.Motif{motif}, phase.Continue)
+
+		checks.CheckHasError(t, result, err, "motif 1 must only use knit (v) and slipped (s or -) stitches, got o")
+	})
+
+	t.Run("generates one row per row pair", func(t *testing.T) {
+		motif, _ := knitting.ParseMotif("vvs")
+
+		result, err := Generate(4, []knitting.Motif{motif}, phase.Continue)
+
+		checks.CheckHasNoError(t, result, err)
+		expected := []string{"vvsv", "vsvv", "svvs", "vvsv", "vsvv", "svvs"}
+		checks.CheckSlicesEqual(t, result.ToStrings(), expected)
+	})
+}
+
+func TestFront(t *testing.T) {
+	colors := [2]rune{'D', 'L'}
+
+	t.Run("empty pattern returns error", func(t *testing.T) {
+		result, err := Front(knitting.Fabric{}, colors)
+
+		checks.CheckHasError(t, result, err, "pattern must not be empty")
+	})
+
+	t.Run("column that is always slipped returns error", func(t *testing.T) {
+		rowPairs := parseRowPairs(t, "vs", "vs")
+
+		result, err := Front(rowPairs, colors)
+
+		checks.CheckHasError(t, result, err, "column 2 is slipped in every row, so it is never knit")
+	})
+
+	t.Run("slipped stitches show the color below", func(t *testing.T) {
+		rowPairs := parseRowPairs(t, "vvvs", "vsvv")
+
+		result, err := Front(rowPairs, colors)
+
+		checks.CheckHasNoError(t, result, err)
+		expected := []string{
+			"LLDL",
+			"LDDD",
+		}
+		checks.CheckSlicesEqual(t, chartStrings(result), expected)
+	})
+
+	t.Run("purls are read as slips", func(t *testing.T) {
+		slipped := parseRowPairs(t, "vvvs", "vsvv")
+		purled := parseRowPairs(t, "vvv-", "v-vv")
+
+		expected, _ := Front(slipped, colors)
+		result, err := Front(purled, colors)
+
+		checks.CheckHasNoError(t, result, err)
+		checks.CheckNestedSlicesEqual(t, result, expected)
+	})
+}
+
+func TestStackedSlips(t *testing.T) {
+	t.Run("single slips are not reported", func(t *testing.T) {
+		rowPairs := parseRowPairs(t, "vs", "sv")
+
+		result := StackedSlips(rowPairs)
+
+		checks.CheckSliceEmpty(t, result)
+	})
+
+	t.Run("finds slips in consecutive row pairs", func(t *testing.T) {
+		rowPairs := parseRowPairs(t, "vvv", "vsv", "vss", "vvv")
+
+		result := StackedSlips(rowPairs)
+
+		checks.CheckSlicesEqual(t, result, []StackedSlip{{RowPair: 2, Column: 2, Height: 4}})
+	})
+
+	t.Run("stacks wrap around the pattern repeat", func(t *testing.T) {
+		rowPairs := parseRowPairs(t, "svv", "svv", "vvv", "svv")
+
+		result := StackedSlips(rowPairs)
+
+		checks.CheckSlicesEqual(t, result, []StackedSlip{{RowPair: 4, Column: 1, Height: 6}})
+	})
+}
@@ -0,0 +1,293 @@
+package phase
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ptrgags/mindless-stitchcraft/knitting"
+	"github.com/ptrgags/mindless-stitchcraft/stitchmath"
+)
+
+// Where a row starts reading the motifs
+type Phase struct {
+	// Index of the motif used for this row
+	Motif int
+	// Index of the first stitch of the row within the motif
+	Start int
+	// If true, the motif is read backwards from Start
+	Reversed bool
+}
+
+// Index into a motif of motifLength stitches of the stitch that is offset
+// stitches after the start of the row
+func (phase Phase) Index(motifLength int, offset int) int {
+	if phase.Reversed {
+		return mod(phase.Start-offset, motifLength)
+	}
+
+	return mod(phase.Start+offset, motifLength)
+}
+
+// Read the stitch that is offset stitches after the start of the row
+func (phase Phase) stitch(motifs []knitting.Motif, offset int) knitting.KnitStitch {
+	motif := motifs[phase.Motif]
+	return motif[phase.Index(len(motif), offset)]
+}
+
+// The phase where the motif continues after reading fabricWidth stitches
+func (phase Phase) advance(fabricWidth int) Phase {
+	result := phase
+	if phase.Reversed {
+		result.Start -= fabricWidth
+	} else {
+		result.Start += fabricWidth
+	}
+
+	return result
+}
+
+func mod(a int, n int) int {
+	return ((a % n) + n) % n
+}
+
+// A rule for where each row starts in the motifs. Next is called for every
+// row after the first, which always starts at the first stitch of the first
+// motif. It is given the row number (starting from 0), where the previous
+// row started, where the motif would continue if the row ended
+// mid-motif, and the list of motifs. Out of range values are wrapped
+// around.
+type Rule struct {
+	Next func(row int, previous Phase, continued Phase, motifs []knitting.Motif) Phase
+	// Next may only depend on the row number modulo Cycle. Use 1 for
+	// rules that do not look at the row number at all.
+	Cycle int
+}
+
+func nextMotif(previous Phase, motifs []knitting.Motif) int {
+	return (previous.Motif + 1) % len(motifs)
+}
+
+// Continue the motif where the previous row ended. This is the zigzag
+// pattern.
+var Continue = Rule{
+	Next: func(row int, previous Phase, continued Phase, motifs []knitting.Motif) Phase {
+		return continued
+	},
+	Cycle: 1,
+}
+
+// Start each row at the beginning of the next motif. This is the sync
+// pattern.
+var Restart = Rule{
+	Next: func(row int, previous Phase, continued Phase, motifs []knitting.Motif) Phase {
+		return Phase{Motif: nextMotif(previous, motifs)}
+	},
+	Cycle: 1,
+}
+
+// Start each row shift stitches further into the motif than the previous
+// row started. Negative values shift backwards.
+func Shift(shift int) Rule {
+	return Rule{
+		Next: func(row int, previous Phase, continued Phase, motifs []knitting.Motif) Phase {
+			return Phase{
+				Motif:    nextMotif(previous, motifs),
+				Start:    previous.Start + shift,
+				Reversed: previous.Reversed,
+			}
+		},
+		Cycle: 1,
+	}
+}
+
+// Continue the motif where the previous row ended, but restart at the
+// beginning of the next motif every n rows.
+func RestartEvery(n int) Rule {
+	return Rule{
+		Next: func(row int, previous Phase, continued Phase, motifs []knitting.Motif) Phase {
+			if row%n == 0 {
+				return Phase{Motif: nextMotif(previous, motifs)}
+			}
+			return continued
+		},
+		Cycle: n,
+	}
+}
+
+// Start each row at the beginning of the next motif, but read the motif
+// backwards (from its last stitch) on every second row.
+var ReverseAlternate = Rule{
+	Next: func(row int, previous Phase, continued Phase, motifs []knitting.Motif) Phase {
+		motif := nextMotif(previous, motifs)
+		if row%2 == 1 {
+			return Phase{Motif: motif, Start: len(motifs[motif]) - 1, Reversed: true}
+		}
+		return Phase{Motif: motif}
+	},
+	Cycle: 2,
+}
+
+// Parse the name of a built-in rule: "continue", "restart", "reverse",
+// "shift:K" or "restart-every:N"
+func ParseRule(name string) (Rule, error) {
+	switch name {
+	case "continue":
+		return Continue, nil
+	case "restart":
+		return Restart, nil
+	case "reverse":
+		return ReverseAlternate, nil
+	}
+
+	ruleName, parameter, found := strings.Cut(name, ":")
+	if !found {
+		return Rule{}, fmt.Errorf("unknown rule %s, must be continue, restart, reverse, shift:K or restart-every:N", name)
+	}
+
+	value, err := strconv.Atoi(parameter)
+	if err != nil {
+		return Rule{}, fmt.Errorf("rule %s must have an integer parameter", name)
+	}
+
+	switch ruleName {
+	case "shift":
+		return Shift(value), nil
+	case "restart-every":
+		if value < 1 {
+			return Rule{}, fmt.Errorf("rule %s must restart every 1 or more rows", name)
+		}
+		return RestartEvery(value), nil
+	default:
+		return Rule{}, fmt.Errorf("unknown rule %s, must be continue, restart, reverse, shift:K or restart-every:N", name)
+	}
+}
+
+// Wrap the motif index and start index into range
+func normalize(phase Phase, motifs []knitting.Motif) Phase {
+	phase.Motif = mod(phase.Motif, len(motifs))
+	phase.Start = mod(phase.Start, len(motifs[phase.Motif]))
+	return phase
+}
+
+func checkMotifsAndRule(motifs []knitting.Motif, rule Rule) error {
+	if len(motifs) < 1 {
+		return errors.New("motifs must be non-empty")
+	}
+
+	for i, motif := range motifs {
+		if len(motif) == 0 {
+			return fmt.Errorf("motif %d must not be empty", i+1)
+		}
+	}
+
+	if rule.Next == nil || rule.Cycle < 1 {
+		return errors.New("rule must have a Next function and a positive Cycle")
+	}
+
+	return nil
+}
+
+// Everything that determines how the rest of the pattern is generated
+type state struct {
+	phase Phase
+	cycle int
+}
+
+// Generate a flat pattern where the rule decides where each row starts in
+// the motifs. Rows are listed as they are worked by the knitter, from the
+// first row to the last, and every second row is worked on the wrong side
+// of the fabric. The phase of each row is returned alongside the fabric.
+//
+// Rows are generated until the pattern repeats, which always takes an even
+// number of rows so the pattern returns to the starting side.
+//
+// Motifs with increases or decreases are rejected if any row would end
+// up with a different number of stitches than fabricWidth.
+func Generate(fabricWidth int, motifs []knitting.Motif, rule Rule) (knitting.Fabric, []Phase, error) {
+	if fabricWidth < 1 {
+		return nil, nil, errors.New("fabricWidth must be a positive integer")
+	}
+
+	if err := checkMotifsAndRule(motifs, rule); err != nil {
+		return nil, nil, err
+	}
+
+	// The state also tracks the row number within the rule's cycle, and
+	// the side of the fabric
+	cycle := int(stitchmath.LCM(uint(rule.Cycle), 2))
+
+	fabric := knitting.Fabric{}
+	phases := []Phase{}
+	seen := map[state]int{}
+	current := Phase{}
+	for row := 0; ; row++ {
+		key := state{current, row % cycle}
+		if first, ok := seen[key]; ok {
+			if first != 0 {
+				return nil, nil, fmt.Errorf("the pattern never returns to the first row: row %d starts the same way as row %d", row+1, first+1)
+			}
+			break
+		}
+		seen[key] = row
+
+		stitches := make(knitting.Row, fabricWidth)
+		for i := range stitches {
+			stitches[i] = current.stitch(motifs, i)
+		}
+		fabric = append(fabric, stitches)
+		phases = append(phases, current)
+
+		continued := normalize(current.advance(fabricWidth), motifs)
+		current = normalize(rule.Next(row+1, current, continued, motifs), motifs)
+	}
+
+	if err := fabric.CheckConstantWidth(fabricWidth); err != nil {
+		return nil, nil, err
+	}
+
+	return fabric, phases, nil
+}
+
+// Generate a shaped pattern where each row has its own width, e.g. a
+// triangle that grows by 2 stitches every other row. Rows are listed in
+// stitching order like Generate, but exactly len(widths) rows are generated
+// since the shape usually never repeats. See shape.FindRepeat for finding
+// where the stitch pattern repeats.
+func GenerateShaped(widths []int, motifs []knitting.Motif, rule Rule) (knitting.Fabric, []Phase, error) {
+	if len(widths) == 0 {
+		return nil, nil, errors.New("widths must be non-empty")
+	}
+
+	for i, width := range widths {
+		if width < 1 {
+			return nil, nil, fmt.Errorf("row %d must have a positive width, got %d", i+1, width)
+		}
+	}
+
+	if err := checkMotifsAndRule(motifs, rule); err != nil {
+		return nil, nil, err
+	}
+
+	fabric := make(knitting.Fabric, len(widths))
+	phases := make([]Phase, len(widths))
+	current := Phase{}
+	for row, width := range widths {
+		stitches := make(knitting.Row, width)
+		for i := range stitches {
+			stitches[i] = current.stitch(motifs, i)
+		}
+		fabric[row] = stitches
+		phases[row] = current
+
+		continued := normalize(current.advance(width), motifs)
+		current = normalize(rule.Next(row+1, current, continued, motifs), motifs)
+	}
+
+	if err := fabric.CheckWidths(widths); err != nil {
+		return nil, nil, err
+	}
+
+	return fabric, phases, nil
+}
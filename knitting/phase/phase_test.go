@@ -0,0 +1,232 @@
+package phase
+
+import (
+	"testing"
+
+	"github.com/ptrgags/mindless-stitchcraft/checks"
+	"github.com/ptrgags/mindless-stitchcraft/knitting"
+)
+
+func parseMotifs(t *testing.T, motifStrs ...string) []knitting.Motif {
+	motifs := make([]knitting.Motif, len(motifStrs))
+	for i, motifStr := range motifStrs {
+		motif, err := knitting.ParseMotif(motifStr)
+		if err != nil {
+			t.Fatalf("invalid motif %s: %v", motifStr, err)
+		}
+		motifs[i] = motif
+	}
+
+	return motifs
+}
+
+func starts(phases []Phase) []int {
+	result := make([]int, len(phases))
+	for i, phase := range phases {
+		result[i] = phase.Start
+	}
+
+	return result
+}
+
+func TestGenerate(t *testing.T) {
+	t.Run("invalid fabricWidth returns error", func(t *testing.T) {
+		motifs := parseMotifs(t, "v-")
+
+		result, _, err := Generate(0, motifs, Continue)
+
+		checks.CheckHasError(t, result, err, "fabricWidth must be a positive integer")
+	})
+
+	t.Run("empty motif list returns error", func(t *testing.T) {
+		result, _, err := Generate(4, []knitting.Motif{}, Continue)
+
+		checks.CheckHasError(t, result, err, "motifs must be non-empty")
+	})
+
+	t.Run("empty motif returns error", func(t *testing.T) {
+		motifs := []knitting.Motif{{knitting.Knit}, {}}
+
+		result, _, err := Generate(4, motifs, Continue)
+
+		checks.CheckHasError(t, result, err, "motif 2 must not be empty")
+	})
+
+	t.Run("incomplete rule returns error", func(t *testing.T) {
+		motifs := parseMotifs(t, "v-")
+
+		result, _, err := Generate(4, motifs, Rule{})
+
+		checks.CheckHasError(t, result, err, "rule must have a Next function and a positive Cycle")
+	})
+
+	t.Run("continue carries the motif across rows", func(t *testing.T) {
+		motifs := parseMotifs(t, "v--")
+
+		result, phases, err := Generate(4, motifs, Continue)
+
+		checks.CheckHasNoError(t, result, err)
+		expected := []string{"v--v", "--v-", "-v--", "v--v", "--v-", "-v--"}
+		checks.CheckSlicesEqual(t, result.ToStrings(), expected)
+		checks.CheckSlicesEqual(t, starts(phases), []int{0, 1, 2, 0, 1, 2})
+	})
+
+	t.Run("restart cycles through the motifs", func(t *testing.T) {
+		motifs := parseMotifs(t, "v-", "vv-")
+
+		result, phases, err := Generate(4, motifs, Restart)
+
+		checks.CheckHasNoError(t, result, err)
+		checks.CheckSlicesEqual(t, result.ToStrings(), []string{"v-v-", "vv-v"})
+		checks.CheckSlicesEqual(t, starts(phases), []int{0, 0})
+	})
+
+	t.Run("restart with an odd number of motifs doubles the rows", func(t *testing.T) {
+		motifs := parseMotifs(t, "v-", "vv-", "v")
+
+		result, _, err := Generate(3, motifs, Restart)
+
+		checks.CheckHasNoError(t, result, err)
+		expected := []string{"v-v", "vv-", "vvv", "v-v", "vv-", "vvv"}
+		checks.CheckSlicesEqual(t, result.ToStrings(), expected)
+	})
+
+	t.Run("shift moves each row further into the motif", func(t *testing.T) {
+		motifs := parseMotifs(t, "vv-")
+
+		result, phases, err := Generate(4, motifs, Shift(1))
+
+		checks.CheckHasNoError(t, result, err)
+		expected := []string{"vv-v", "v-vv", "-vv-", "vv-v", "v-vv", "-vv-"}
+		checks.CheckSlicesEqual(t, result.ToStrings(), expected)
+		checks.CheckSlicesEqual(t, starts(phases), []int{0, 1, 2, 0, 1, 2})
+	})
+
+	t.Run("negative shift moves backwards through the motif", func(t *testing.T) {
+		motifs := parseMotifs(t, "vv-")
+
+		_, phases, err := Generate(4, motifs, Shift(-1))
+
+		checks.CheckHasNoError(t, phases, err)
+		checks.CheckSlicesEqual(t, starts(phases), []int{0, 2, 1, 0, 2, 1})
+	})
+
+	t.Run("restart every n rows continues in between", func(t *testing.T) {
+		motifs := parseMotifs(t, "v--")
+
+		result, phases, err := Generate(4, motifs, RestartEvery(2))
+
+		checks.CheckHasNoError(t, result, err)
+		checks.CheckSlicesEqual(t, result.ToStrings(), []string{"v--v", "--v-"})
+		checks.CheckSlicesEqual(t, starts(phases), []int{0, 1})
+	})
+
+	t.Run("reverse alternate reads every second row backwards", func(t *testing.T) {
+		motifs := parseMotifs(t, "vv-")
+
+		result, phases, err := Generate(4, motifs, ReverseAlternate)
+
+		checks.CheckHasNoError(t, result, err)
+		checks.CheckSlicesEqual(t, result.ToStrings(), []string{"vv-v", "-vv-"})
+		if phases[0].Reversed || !phases[1].Reversed {
+			t.Errorf("expected only the second row to be reversed, got %v", phases)
+		}
+	})
+
+	t.Run("custom rule that never returns to the first row returns error", func(t *testing.T) {
+		motifs := parseMotifs(t, "vv-")
+		rule := Rule{
+			Next: func(row int, previous Phase, continued Phase, motifs []knitting.Motif) Phase {
+				return Phase{Start: 1}
+			},
+			Cycle: 1,
+		}
+
+		result, _, err := Generate(4, motifs, rule)
+
+		checks.CheckHasError(t, result, err, "the pattern never returns to the first row: row 4 starts the same way as row 2")
+	})
+
+	t.Run("motif that changes the row width returns error", func(t *testing.T) {
+		motifs := parseMotifs(t, "vo")
+
+		result, _, err := Generate(4, motifs, Restart)
+
+		checks.CheckHasError(t, result, err, "row 1 would change the row width: it works 2 stitches and leaves 4, but the fabric is 4 stitches wide")
+	})
+}
+
+func TestParseRule(t *testing.T) {
+	t.Run("parses built-in rules", func(t *testing.T) {
+		motifs := parseMotifs(t, "vv-")
+		cases := []struct {
+			name     string
+			expected []int
+		}{
+			{"continue", []int{0, 1, 2, 0, 1, 2}},
+			{"restart", []int{0, 0}},
+			{"reverse", []int{0, 2}},
+			{"shift:2", []int{0, 2, 1, 0, 2, 1}},
+			{"restart-every:3", []int{0, 1, 2, 0, 1, 2}},
+		}
+
+		for _, tc := range cases {
+			t.Run(tc.name, func(t *testing.T) {
+				rule, err := ParseRule(tc.name)
+				checks.CheckHasNoError(t, rule, err)
+
+				_, phases, err := Generate(4, motifs, rule)
+
+				checks.CheckHasNoError(t, phases, err)
+				checks.CheckSlicesEqual(t, starts(phases), tc.expected)
+			})
+		}
+	})
+
+	t.Run("unknown rule returns error", func(t *testing.T) {
+		result, err := ParseRule("spiral")
+
+		checks.CheckHasError(t, result, err, "unknown rule spiral, must be continue, restart, reverse, shift:K or restart-every:N")
+	})
+
+	t.Run("non-integer parameter returns error", func(t *testing.T) {
+		result, err := ParseRule("shift:x")
+
+		checks.CheckHasError(t, result, err, "rule shift:x must have an integer parameter")
+	})
+
+	t.Run("restart-every must be positive", func(t *testing.T) {
+		result, err := ParseRule("restart-every:0")
+
+		checks.CheckHasError(t, result, err, "rule restart-every:0 must restart every 1 or more rows")
+	})
+}
+
+func TestGenerateShaped(t *testing.T) {
+	t.Run("invalid width returns error", func(t *testing.T) {
+		motifs := parseMotifs(t, "v-")
+
+		result, _, err := GenerateShaped([]int{3, 0}, motifs, Continue)
+
+		checks.CheckHasError(t, result, err, "row 2 must have a positive width, got 0")
+	})
+
+	t.Run("continue carries the motif across rows of different widths", func(t *testing.T) {
+		motifs := parseMotifs(t, "v--")
+
+		result, phases, err := GenerateShaped([]int{1, 3, 5}, motifs, Continue)
+
+		checks.CheckHasNoError(t, result, err)
+		checks.CheckSlicesEqual(t, result.ToStrings(), []string{"v", "--v", "--v--"})
+		checks.CheckSlicesEqual(t, starts(phases), []int{0, 1, 1})
+	})
+
+	t.Run("restart starts every row at the beginning", func(t *testing.T) {
+		motifs := parseMotifs(t, "v--")
+
+		result, _, err := GenerateShaped([]int{2, 4}, motifs, Restart)
+
+		checks.CheckHasNoError(t, result, err)
+		checks.CheckSlicesEqual(t, result.ToStrings(), []string{"v-", "v--v"})
+	})
+}
@@ -0,0 +1,194 @@
+package plan
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/ptrgags/mindless-stitchcraft/knitting"
+	"github.com/ptrgags/mindless-stitchcraft/knitting/symmetry"
+	"github.com/ptrgags/mindless-stitchcraft/knitting/sync"
+	"github.com/ptrgags/mindless-stitchcraft/knitting/zigzag"
+)
+
+// A knitted stitch uses roughly this many times its width in yarn. This
+// is only a ballpark figure, it depends on the yarn and the stitch.
+const yarnPerStitchWidth = 3.5
+
+type Pattern int
+
+const (
+	Zigzag Pattern = iota
+	Sync
+)
+
+func ParsePattern(name string) (Pattern, error) {
+	switch name {
+	case "zigzag":
+		return Zigzag, nil
+	case "sync":
+		return Sync, nil
+	default:
+		return Zigzag, fmt.Errorf("pattern must be zigzag or sync, got %s", name)
+	}
+}
+
+// How many stitches and rows make 10 cm of fabric
+type Gauge struct {
+	Stitches float64
+	Rows     float64
+}
+
+// Dimensions of the finished piece in cm
+type Target struct {
+	Width  float64
+	Length float64
+}
+
+// A possible cast-on width for the project
+type Candidate struct {
+	// How many stitches to cast on
+	Stitches int
+	// Width of the fabric in cm
+	Width float64
+	// How many rows until the pattern repeats
+	RepeatRows int
+	// Height of one repeat in cm
+	RepeatHeight float64
+	// How many complete repeats fit in the target length
+	FullRepeats int
+	// Wallpaper group of the pattern, see the symmetry package
+	Symmetry string
+	// False if the pattern is only vertical or horizontal stripes, such
+	// as ribbing
+	Interesting bool
+	// Rough estimate of the yarn needed for the target length, in meters
+	YarnMeters float64
+}
+
+func generateChart(pattern Pattern, motif knitting.Motif, stitches int) (knitting.Fabric, error) {
+	if pattern == Sync {
+		return sync.GenerateFabric(uint(stitches), []knitting.Motif{motif})
+	}
+
+	return zigzag.GenerateZigzagFabric(motif, stitches)
+}
+
+// A chart is interesting if it has a row with both knits and purls and a
+// column with both knits and purls, i.e. it is not just stripes.
+func isInteresting(chart knitting.Fabric) bool {
+	mixedRow := false
+	for _, row := range chart {
+		for _, stitch := range row {
+			if stitch != row[0] {
+				mixedRow = true
+			}
+		}
+	}
+
+	mixedColumn := false
+	for _, row := range chart {
+		for i, stitch := range row {
+			if stitch != chart[0][i] {
+				mixedColumn = true
+			}
+		}
+	}
+
+	return mixedRow && mixedColumn
+}
+
+func evaluate(pattern Pattern, motif knitting.Motif, stitches int, gauge Gauge, targetRows int) (Candidate, error) {
+	chart, err := generateChart(pattern, motif, stitches)
+	if err != nil {
+		return Candidate{}, err
+	}
+
+	report, err := symmetry.Classify(chart)
+	if err != nil {
+		return Candidate{}, err
+	}
+
+	stitchWidth := 10 / gauge.Stitches
+	repeatRows := len(chart)
+	return Candidate{
+		Stitches:     stitches,
+		Width:        float64(stitches) * stitchWidth,
+		RepeatRows:   repeatRows,
+		RepeatHeight: float64(repeatRows) * 10 / gauge.Rows,
+		FullRepeats:  targetRows / repeatRows,
+		Symmetry:     report.Group,
+		Interesting:  isInteresting(chart),
+		YarnMeters:   float64(stitches*targetRows) * yarnPerStitchWidth * stitchWidth / 100,
+	}, nil
+}
+
+// Suggest cast-on widths within spread stitches of the target width.
+// Candidates are ranked with interesting patterns first, then by how
+// quickly the pattern repeats, then by how close they are to the target
+// width.
+//
+// Widths where the motif would change the number of stitches in a row
+// are skipped.
+func Plan(pattern Pattern, motif knitting.Motif, gauge Gauge, target Target, spread int) ([]Candidate, error) {
+	if len(motif) == 0 {
+		return nil, errors.New("motif must not be empty")
+	}
+
+	if gauge.Stitches <= 0 || gauge.Rows <= 0 {
+		return nil, errors.New("gauge must be positive")
+	}
+
+	if target.Width <= 0 || target.Length <= 0 {
+		return nil, errors.New("target dimensions must be positive")
+	}
+
+	if spread < 0 {
+		return nil, errors.New("spread must not be negative")
+	}
+
+	targetStitches := int(math.Round(target.Width * gauge.Stitches / 10))
+	targetRows := int(math.Round(target.Length * gauge.Rows / 10))
+
+	candidates := []Candidate{}
+	for stitches := max(targetStitches-spread, 1); stitches <= targetStitches+spread; stitches++ {
+		candidate, err := evaluate(pattern, motif, stitches, gauge, targetRows)
+		var widthErr *knitting.WidthError
+		if errors.As(err, &widthErr) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%d stitches: %w", stitches, err)
+		}
+		candidates = append(candidates, candidate)
+	}
+
+	if len(candidates) == 0 {
+		return nil, errors.New("the motif does not keep a constant row width for any of the candidate widths")
+	}
+
+	distance := func(candidate Candidate) int {
+		difference := candidate.Stitches - targetStitches
+		if difference < 0 {
+			return -difference
+		}
+		return difference
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a := candidates[i]
+		b := candidates[j]
+		if a.Interesting != b.Interesting {
+			return a.Interesting
+		}
+
+		if a.RepeatRows != b.RepeatRows {
+			return a.RepeatRows < b.RepeatRows
+		}
+
+		return distance(a) < distance(b)
+	})
+
+	return candidates, nil
+}
@@ -0,0 +1,129 @@
+package plan
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ptrgags/mindless-stitchcraft/checks"
+	"github.com/ptrgags/mindless-stitchcraft/knitting"
+	"github.com/ptrgags/mindless-stitchcraft/knitting/zigzag"
+)
+
+func TestParsePattern(t *testing.T) {
+	t.Run("parses pattern names", func(t *testing.T) {
+		zigzagPattern, err := ParsePattern("zigzag")
+		checks.CheckHasNoError(t, zigzagPattern, err)
+		syncPattern, err := ParsePattern("sync")
+		checks.CheckHasNoError(t, syncPattern, err)
+
+		if zigzagPattern != Zigzag || syncPattern != Sync {
+			t.Errorf("expected (Zigzag, Sync), got (%v, %v)", zigzagPattern, syncPattern)
+		}
+	})
+
+	t.Run("unknown pattern returns error", func(t *testing.T) {
+		result, err := ParsePattern("spiral")
+
+		checks.CheckHasError(t, result, err, "pattern must be zigzag or sync, got spiral")
+	})
+}
+
+func TestPlan(t *testing.T) {
+	gauge := Gauge{Stitches: 20, Rows: 30}
+	target := Target{Width: 10, Length: 20}
+
+	t.Run("invalid gauge returns error", func(t *testing.T) {
+		motif, _ := knitting.ParseMotif("v--")
+
+		result, err := Plan(Zigzag, motif, Gauge{0, 30}, target, 2)
+
+		checks.CheckHasError(t, result, err, "gauge must be positive")
+	})
+
+	t.Run("invalid target returns error", func(t *testing.T) {
+		motif, _ := knitting.ParseMotif("v--")
+
+		result, err := Plan(Zigzag, motif, gauge, Target{10, 0}, 2)
+
+		checks.CheckHasError(t, result, err, "target dimensions must be positive")
+	})
+
+	t.Run("negative spread returns error", func(t *testing.T) {
+		motif, _ := knitting.ParseMotif("v--")
+
+		result, err := Plan(Zigzag, motif, gauge, target, -1)
+
+		checks.CheckHasError(t, result, err, "spread must not be negative")
+	})
+
+	t.Run("motif that changes the row width returns error", func(t *testing.T) {
+		motif, _ := knitting.ParseMotif("vo")
+
+		result, err := Plan(Sync, motif, gauge, target, 2)
+
+		checks.CheckHasError(t, result, err, "the motif does not keep a constant row width for any of the candidate widths")
+	})
+
+	t.Run("lists every width within the spread", func(t *testing.T) {
+		motif, _ := knitting.ParseMotif("v--")
+
+		result, err := Plan(Zigzag, motif, gauge, target, 3)
+
+		checks.CheckHasNoError(t, result, err)
+		seen := map[int]bool{}
+		for _, candidate := range result {
+			seen[candidate.Stitches] = true
+		}
+		for stitches := 17; stitches <= 23; stitches++ {
+			if !seen[stitches] {
+				t.Errorf("expected a candidate with %d stitches", stitches)
+			}
+		}
+	})
+
+	t.Run("computes physical sizes and yarn", func(t *testing.T) {
+		motif, _ := knitting.ParseMotif("v--")
+
+		result, err := Plan(Zigzag, motif, gauge, target, 0)
+
+		checks.CheckHasNoError(t, result, err)
+		candidate := result[0]
+		analysis, _ := zigzag.AnalyzePattern(3, 20)
+		if candidate.Stitches != 20 || candidate.Width != 10 {
+			t.Errorf("expected 20 stitches and 10 cm, got %d and %v", candidate.Stitches, candidate.Width)
+		}
+		if candidate.RepeatRows != analysis.RowRepeat {
+			t.Errorf("expected a repeat of %d rows, got %d", analysis.RowRepeat, candidate.RepeatRows)
+		}
+		if candidate.RepeatHeight != 2 || candidate.FullRepeats != 10 {
+			t.Errorf("expected 2 cm repeats that fit 10 times, got %v cm and %d", candidate.RepeatHeight, candidate.FullRepeats)
+		}
+		// 20 stitches * 60 rows * 3.5 * 0.5 cm
+		if math.Abs(candidate.YarnMeters-21) > 1e-9 {
+			t.Errorf("expected 21 m of yarn, got %v", candidate.YarnMeters)
+		}
+	})
+
+	t.Run("ranks interesting patterns with short repeats first", func(t *testing.T) {
+		motif, _ := knitting.ParseMotif("v-")
+
+		result, err := Plan(Sync, motif, gauge, target, 2)
+
+		checks.CheckHasNoError(t, result, err)
+		// Even widths give ribbing, odd widths give a checkerboard
+		if !result[0].Interesting || result[0].Stitches%2 != 1 {
+			t.Errorf("expected an odd width first, got %v", result[0])
+		}
+		last := result[len(result)-1]
+		if last.Interesting || last.Stitches%2 != 0 {
+			t.Errorf("expected an even width last, got %v", last)
+		}
+		for i := 1; i < len(result); i++ {
+			a := result[i-1]
+			b := result[i]
+			if a.Interesting == b.Interesting && a.RepeatRows > b.RepeatRows {
+				t.Errorf("expected shorter repeats first, got %v before %v", a, b)
+			}
+		}
+	})
+}
@@ -0,0 +1,134 @@
+package round
+
+import (
+	"errors"
+
+	"github.com/ptrgags/mindless-stitchcraft/knitting"
+)
+
+// Describes where the motif is at the beginning of a round. Since knitting
+// in the round is a spiral, this is where the jog between rounds appears.
+//
+// The pattern repeats, so the first round is compared against the last
+// round of the previous repeat.
+type RoundStart struct {
+	// Which motif is worked in this round. This is always 0 for spiral
+	// patterns.
+	Motif int
+	// Index into the motif of the first stitch of the round
+	MotifIndex int
+	// How many stitches the motif shifts compared to the stitch directly
+	// below the beginning of the round. Only spiral patterns drift.
+	Drift int
+	// How many stitches were missing from the last repeat of the previous
+	// round when the motif is restarted. Only restarted patterns are cut.
+	Cut int
+}
+
+// Fill a single round with the motif, starting at the given index into
+// the motif.
+func fillRound(motif knitting.Motif, start int, circumference int) knitting.Row {
+	row := make(knitting.Row, circumference)
+	for i := range row {
+		row[i] = motif[(start+i)%len(motif)]
+	}
+
+	return row
+}
+
+// Repeat the motif continuously around the tube until the motif
+// lines up with the beginning of the round again. Rows are listed in
+// stitching order.
+func generateSpiralRounds(motif knitting.Motif, circumference int) (knitting.Fabric, []RoundStart) {
+	n := len(motif)
+	drift := circumference % n
+
+	fabric := knitting.Fabric{}
+	starts := []RoundStart{}
+	start := 0
+	for {
+		fabric = append(fabric, fillRound(motif, start, circumference))
+		starts = append(starts, RoundStart{
+			Motif:      0,
+			MotifIndex: start,
+			Drift:      drift,
+			Cut:        0,
+		})
+
+		start = (start + circumference) % n
+		if start == 0 {
+			break
+		}
+	}
+
+	return fabric, starts
+}
+
+// Generate a pattern where the motif is repeated continuously around a
+// tube. Since the work is never turned, no rows are reversed. If the motif
+// does not evenly divide the circumference, the pattern spirals.
+//
+// This returns the chart of the fabric (as seen from the outside) and
+// where the motif is at the start of each round, listed from the first
+// round to the last.
+func GenerateSpiralPattern(motif knitting.Motif, circumference int) ([]string, []RoundStart, error) {
+	if circumference < 1 {
+		return nil, nil, errors.New("circumference must be a positive integer")
+	}
+
+	if len(motif) == 0 {
+		return nil, nil, errors.New("motif must not be empty")
+	}
+
+	fabric, starts := generateSpiralRounds(motif, circumference)
+	if err := fabric.CheckConstantWidth(circumference); err != nil {
+		return nil, nil, err
+	}
+
+	return fabric.Rotate180().ToStrings(), starts, nil
+}
+
+// Generate a pattern where each round restarts at the beginning of the
+// motif, similar to knit-sync. If multiple motifs are given, the pattern
+// cycles through them one round at a time.
+//
+// This returns the chart of the fabric (as seen from the outside) and
+// where the motif is at the start of each round, listed from the first
+// round to the last.
+func GenerateRestartPattern(circumference uint, motifs []knitting.Motif) ([]string, []RoundStart, error) {
+	if circumference < 1 {
+		return []string{}, []RoundStart{}, errors.New("circumference must be positive")
+	}
+
+	length := len(motifs)
+	if length < 1 {
+		return []string{}, []RoundStart{}, errors.New("motifs must be non-empty")
+	}
+
+	fabric := make(knitting.Fabric, length)
+	starts := make([]RoundStart, length)
+	for i, motif := range motifs {
+		fabric[i] = knitting.Row(motif.RepeatToLength(circumference))
+
+		// The last round wraps around to the first one
+		previous := motifs[(i+length-1)%length]
+		remainder := int(circumference) % len(previous)
+		cut := 0
+		if remainder != 0 {
+			cut = len(previous) - remainder
+		}
+
+		starts[i] = RoundStart{
+			Motif:      i,
+			MotifIndex: 0,
+			Drift:      0,
+			Cut:        cut,
+		}
+	}
+
+	if err := fabric.CheckConstantWidth(int(circumference)); err != nil {
+		return []string{}, []RoundStart{}, err
+	}
+
+	return fabric.Rotate180().ToStrings(), starts, nil
+}
@@ -0,0 +1,155 @@
+package round
+
+import (
+	"testing"
+
+	"github.com/ptrgags/mindless-stitchcraft/checks"
+	"github.com/ptrgags/mindless-stitchcraft/knitting"
+)
+
+func TestGenerateSpiralPattern(t *testing.T) {
+	t.Run("invalid circumference returns error", func(t *testing.T) {
+		validMotif, _ := knitting.ParseMotif("v--")
+		cases := []struct {
+			label         string
+			circumference int
+		}{
+			{"zero", 0},
+			{"negative", -1},
+		}
+
+		for _, tc := range cases {
+			t.Run(tc.label, func(t *testing.T) {
+				rows, _, err := GenerateSpiralPattern(validMotif, tc.circumference)
+
+				checks.CheckHasError(t, rows, err, "circumference must be a positive integer")
+			})
+		}
+	})
+
+	t.Run("generated pattern is the correct shape", func(t *testing.T) {
+		// No rows are flipped, so the height is
+		// len(motif) / gcd(len(motif), circumference)
+		// without doubling
+		cases := []struct {
+			label          string
+			motif          string
+			circumference  int
+			expectedHeight int
+		}{
+			{"motif divides circumference", "v--", 9, 1},
+			{"coprime lengths", "v--", 10, 3},
+			{"noncoprime lengths", "vv----", 8, 3},
+			{"motif longer than circumference", "vvvv----", 3, 8},
+		}
+
+		for _, tc := range cases {
+			t.Run(tc.label, func(t *testing.T) {
+				motif, _ := knitting.ParseMotif(tc.motif)
+				rows, _, err := GenerateSpiralPattern(motif, tc.circumference)
+
+				checks.CheckHasNoError(t, rows, err)
+				checks.CheckStringGridShape(t, rows, tc.circumference, tc.expectedHeight)
+			})
+		}
+	})
+
+	t.Run("rounds are never flipped", func(t *testing.T) {
+		motif, _ := knitting.ParseMotif("v--")
+
+		rows, _, err := GenerateSpiralPattern(motif, 4)
+
+		expectedRows := []string{
+			"--v-",
+			"-v--",
+			"v--v",
+		}
+		checks.CheckHasNoError(t, rows, err)
+		checks.CheckSlicesEqual(t, rows, expectedRows)
+	})
+
+	t.Run("reports where each round starts in the motif", func(t *testing.T) {
+		motif, _ := knitting.ParseMotif("v---")
+
+		_, starts, err := GenerateSpiralPattern(motif, 6)
+
+		expectedStarts := []RoundStart{
+			{Motif: 0, MotifIndex: 0, Drift: 2, Cut: 0},
+			{Motif: 0, MotifIndex: 2, Drift: 2, Cut: 0},
+		}
+		checks.CheckHasNoError(t, starts, err)
+		checks.CheckSlicesEqual(t, starts, expectedStarts)
+	})
+
+	t.Run("motif that divides the circumference has no drift", func(t *testing.T) {
+		motif, _ := knitting.ParseMotif("v--")
+
+		_, starts, err := GenerateSpiralPattern(motif, 6)
+
+		expectedStarts := []RoundStart{
+			{Motif: 0, MotifIndex: 0, Drift: 0, Cut: 0},
+		}
+		checks.CheckHasNoError(t, starts, err)
+		checks.CheckSlicesEqual(t, starts, expectedStarts)
+	})
+}
+
+func TestGenerateRestartPattern(t *testing.T) {
+	t.Run("circumference = zero results in error", func(t *testing.T) {
+		motif, _ := knitting.ParseMotif("v--")
+
+		result, _, err := GenerateRestartPattern(0, []knitting.Motif{motif})
+
+		checks.CheckHasError(t, result, err, "circumference must be positive")
+	})
+
+	t.Run("No motifs results in error", func(t *testing.T) {
+		result, _, err := GenerateRestartPattern(10, []knitting.Motif{})
+
+		checks.CheckHasError(t, result, err, "motifs must be non-empty")
+	})
+
+	t.Run("single motif produces a single round", func(t *testing.T) {
+		motif, _ := knitting.ParseMotif("v--")
+
+		result, _, err := GenerateRestartPattern(10, []knitting.Motif{motif})
+
+		expectedPattern := []string{
+			"v--v--v--v",
+		}
+		checks.CheckHasNoError(t, result, err)
+		checks.CheckSlicesEqual(t, result, expectedPattern)
+	})
+
+	t.Run("multiple motifs cycle without flipping", func(t *testing.T) {
+		first, _ := knitting.ParseMotif("v--")
+		second, _ := knitting.ParseMotif("vv-")
+
+		result, _, err := GenerateRestartPattern(4, []knitting.Motif{first, second})
+
+		expectedPattern := []string{
+			"v-vv",
+			"v--v",
+		}
+		checks.CheckHasNoError(t, result, err)
+		checks.CheckSlicesEqual(t, result, expectedPattern)
+	})
+
+	t.Run("reports how much of the last repeat was cut", func(t *testing.T) {
+		first, _ := knitting.ParseMotif("v--")
+		second, _ := knitting.ParseMotif("vv--")
+
+		_, starts, err := GenerateRestartPattern(10, []knitting.Motif{first, second})
+
+		// The round before the first is the second motif:
+		// 10 % 4 = 2 so 2 stitches were missing.
+		// The round before the second is the first motif:
+		// 10 % 3 = 1 so 2 stitches were missing
+		expectedStarts := []RoundStart{
+			{Motif: 0, MotifIndex: 0, Drift: 0, Cut: 2},
+			{Motif: 1, MotifIndex: 0, Drift: 0, Cut: 2},
+		}
+		checks.CheckHasNoError(t, starts, err)
+		checks.CheckSlicesEqual(t, starts, expectedStarts)
+	})
+}
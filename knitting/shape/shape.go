@@ -0,0 +1,159 @@
+package shape
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ptrgags/mindless-stitchcraft/knitting"
+	"github.com/ptrgags/mindless-stitchcraft/knitting/phase"
+)
+
+// Shaped pieces are limited to this many rows
+const MaxRows = 1000
+
+// Rows of shaped pieces are limited to this many stitches
+const MaxWidth = 10000
+
+// Matches a linear schedule like "3+2/2x20" or "41-2x20"
+var linearPattern = regexp.MustCompile(`^(\d+)([+-]\d+)(?:/(\d+))?x(\d+)$`)
+
+// Parse a width schedule, which lists the width of each row in stitching
+// order. It is written either as a list of widths like "5,7,9,11", or as a
+// linear formula START+STEP/EVERYxROWS: start with START stitches and
+// change the width by STEP every EVERY rows (default 1), for ROWS rows.
+// E.g. "3+2/2x20" is a triangle that grows by 2 stitches every right side
+// row.
+func ParseSchedule(text string) ([]int, error) {
+	invalid := fmt.Errorf("width schedule %s must be a list of widths like 5,7,9 or a formula like 3+2/2x20", text)
+
+	if matches := linearPattern.FindStringSubmatch(text); matches != nil {
+		// START, STEP, EVERY and ROWS. EVERY defaults to 1
+		if matches[3] == "" {
+			matches[3] = "1"
+		}
+
+		values := make([]int, 4)
+		for i := range values {
+			value, err := strconv.Atoi(matches[i+1])
+			if err != nil {
+				return nil, invalid
+			}
+			values[i] = value
+		}
+
+		return Linear(values[0], values[1], values[2], values[3])
+	}
+
+	widths := []int{}
+	for _, field := range strings.Split(text, ",") {
+		width, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil {
+			return nil, invalid
+		}
+		widths = append(widths, width)
+	}
+
+	return widths, checkWidths(widths)
+}
+
+// A schedule that starts with start stitches and changes by step stitches
+// every few rows. The first change happens at the start of the second
+// group of rows, e.g. with every = 2 the widths change on rows 3, 5, 7...
+func Linear(start int, step int, every int, rows int) ([]int, error) {
+	if every < 1 {
+		return nil, errors.New("the width must change every 1 or more rows")
+	}
+
+	if rows < 1 || rows > MaxRows {
+		return nil, fmt.Errorf("rows must be between 1 and %d", MaxRows)
+	}
+
+	// Keep the widths from overflowing before checkWidths sees them
+	if start > MaxWidth || step < -MaxWidth || step > MaxWidth {
+		return nil, fmt.Errorf("the start width and step must be at most %d stitches", MaxWidth)
+	}
+
+	widths := make([]int, rows)
+	for i := range widths {
+		widths[i] = start + step*(i/every)
+	}
+
+	return widths, checkWidths(widths)
+}
+
+func checkWidths(widths []int) error {
+	if len(widths) > MaxRows {
+		return fmt.Errorf("rows must be between 1 and %d", MaxRows)
+	}
+
+	for i, width := range widths {
+		if width < 1 || width > MaxWidth {
+			return fmt.Errorf("the width schedule reaches %d stitches at row %d, must be between 1 and %d", width, i+1, MaxWidth)
+		}
+	}
+
+	return nil
+}
+
+// Chart a shaped fabric given in stitching order, with the first row at
+// the bottom. Stitches are added or removed at the left edge of the chart,
+// i.e. at the end of right side rows and the start of wrong side rows, so
+// the rows are padded on the left with spaces and the right edges line up.
+func Chart(stitchingOrder knitting.Fabric) []string {
+	chart := stitchingOrder.HandleReverseRows().Rotate180()
+
+	widest := 0
+	for _, row := range chart {
+		widest = max(widest, len(row))
+	}
+
+	result := make([]string, len(chart))
+	for i, row := range chart {
+		result[i] = fmt.Sprintf("%*s", widest, row.ToString())
+	}
+
+	return result
+}
+
+// Where the stitch pattern of a shaped fabric repeats
+type Repeat struct {
+	// How many rows the stitch pattern takes to repeat. This is always
+	// even so the repeat starts on the same side of the fabric.
+	Rows int
+	// How much wider the fabric is after each repeat. Negative values mean
+	// the fabric gets narrower.
+	Growth int
+}
+
+// Find the shortest repeat of the stitch pattern of a shaped fabric. The
+// rows can't repeat exactly since the widths change, but the pattern
+// repeats once the motif starts the same way on the same side and the
+// width keeps changing the same way. The repeat must fit in the fabric at
+// least twice to be found. The second return value is false if there is
+// no repeat.
+func FindRepeat(widths []int, phases []phase.Phase) (Repeat, bool) {
+	for period := 2; 2*period <= len(phases); period += 2 {
+		if repeatsEvery(widths, phases, period) {
+			return Repeat{period, widths[period] - widths[0]}, true
+		}
+	}
+
+	return Repeat{}, false
+}
+
+func repeatsEvery(widths []int, phases []phase.Phase, period int) bool {
+	for row := 0; row+period < len(phases); row++ {
+		if phases[row] != phases[row+period] {
+			return false
+		}
+
+		if row > 0 && widths[row]-widths[row-1] != widths[row+period]-widths[row+period-1] {
+			return false
+		}
+	}
+
+	return true
+}
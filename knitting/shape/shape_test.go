@@ -0,0 +1,143 @@
+package shape
+
+import (
+	"testing"
+
+	"github.com/ptrgags/mindless-stitchcraft/checks"
+	"github.com/ptrgags/mindless-stitchcraft/knitting"
+	"github.com/ptrgags/mindless-stitchcraft/knitting/phase"
+)
+
+func TestParseSchedule(t *testing.T) {
+	t.Run("parses a list of widths", func(t *testing.T) {
+		result, err := ParseSchedule("5,7, 9")
+
+		checks.CheckHasNoError(t, result, err)
+		checks.CheckSlicesEqual(t, result, []int{5, 7, 9})
+	})
+
+	t.Run("parses a linear formula", func(t *testing.T) {
+		cases := []struct {
+			text     string
+			expected []int
+		}{
+			{"3+2/2x6", []int{3, 3, 5, 5, 7, 7}},
+			{"9-1x4", []int{9, 8, 7, 6}},
+			{"4+0x2", []int{4, 4}},
+		}
+
+		for _, tc := range cases {
+			t.Run(tc.text, func(t *testing.T) {
+				result, err := ParseSchedule(tc.text)
+
+				checks.CheckHasNoError(t, result, err)
+				checks.CheckSlicesEqual(t, result, tc.expected)
+			})
+		}
+	})
+
+	t.Run("invalid schedule returns error", func(t *testing.T) {
+		result, err := ParseSchedule("3+2/2")
+
+		checks.CheckHasError(t, result, err, "width schedule 3+2/2 must be a list of widths like 5,7,9 or a formula like 3+2/2x20")
+	})
+
+	t.Run("numbers that are too large return error", func(t *testing.T) {
+		result, err := ParseSchedule("99999999999999999999+2x4")
+
+		checks.CheckHasError(t, result, err, "width schedule 99999999999999999999+2x4 must be a list of widths like 5,7,9 or a formula like 3+2/2x20")
+	})
+
+	t.Run("shrinking to nothing returns error", func(t *testing.T) {
+		result, err := ParseSchedule("3-2/2x6")
+
+		checks.CheckHasError(t, result, err, "the width schedule reaches -1 stitches at row 5, must be between 1 and 10000")
+	})
+
+	t.Run("widths that are too large return error", func(t *testing.T) {
+		cases := []struct {
+			text     string
+			expected string
+		}{
+			{"1+4611686018427387904x3", "the start width and step must be at most 10000 stitches"},
+			{"10001,5", "the width schedule reaches 10001 stitches at row 1, must be between 1 and 10000"},
+			{"9000+1000x3", "the width schedule reaches 11000 stitches at row 3, must be between 1 and 10000"},
+		}
+
+		for _, tc := range cases {
+			t.Run(tc.text, func(t *testing.T) {
+				result, err := ParseSchedule(tc.text)
+
+				checks.CheckHasError(t, result, err, tc.expected)
+			})
+		}
+	})
+
+	t.Run("too many rows returns error", func(t *testing.T) {
+		result, err := ParseSchedule("3+1x1001")
+
+		checks.CheckHasError(t, result, err, "rows must be between 1 and 1000")
+	})
+
+	t.Run("invalid every returns error", func(t *testing.T) {
+		result, err := ParseSchedule("3+1/0x4")
+
+		checks.CheckHasError(t, result, err, "the width must change every 1 or more rows")
+	})
+}
+
+func TestChart(t *testing.T) {
+	stitchingOrder := knitting.Fabric{
+		{knitting.Knit},
+		{knitting.Knit, knitting.Knit, knitting.Purl},
+		{knitting.Knit, knitting.Purl, knitting.Purl, knitting.Purl, knitting.Knit},
+	}
+
+	result := Chart(stitchingOrder)
+
+	// The middle row is worked on the wrong side
+	expected := []string{
+		"v---v",
+		"  --v",
+		"    v",
+	}
+	checks.CheckSlicesEqual(t, result, expected)
+}
+
+func TestFindRepeat(t *testing.T) {
+	t.Run("growing triangle repeats", func(t *testing.T) {
+		motif, _ := knitting.ParseMotif("v--")
+		widths, _ := Linear(3, 2, 2, 12)
+		_, phases, _ := phase.GenerateShaped(widths, []knitting.Motif{motif}, phase.Continue)
+
+		result, found := FindRepeat(widths, phases)
+
+		if !found || result != (Repeat{6, 6}) {
+			t.Errorf("expected a 6 row repeat growing by 6 stitches, got %v", result)
+		}
+	})
+
+	t.Run("irregular widths do not repeat", func(t *testing.T) {
+		motif, _ := knitting.ParseMotif("v-")
+		widths := []int{5, 7, 9, 6}
+		_, phases, _ := phase.GenerateShaped(widths, []knitting.Motif{motif}, phase.Continue)
+
+		_, found := FindRepeat(widths, phases)
+
+		if found {
+			t.Errorf("expected no repeat")
+		}
+	})
+
+	t.Run("repeat must fit twice", func(t *testing.T) {
+		motif, _ := knitting.ParseMotif("v--")
+		widths, _ := Linear(3, 2, 2, 10)
+		_, phases, _ := phase.GenerateShaped(widths, []knitting.Motif{motif}, phase.Continue)
+
+		_, found := FindRepeat(widths, phases)
+
+		if found {
+			t.Errorf("expected no repeat in 10 rows")
+		}
+	})
+}
@@ -0,0 +1,280 @@
+package solve
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/ptrgags/mindless-stitchcraft/knitting"
+	"github.com/ptrgags/mindless-stitchcraft/knitting/round"
+	knitsync "github.com/ptrgags/mindless-stitchcraft/knitting/sync"
+	"github.com/ptrgags/mindless-stitchcraft/knitting/zigzag"
+	"github.com/ptrgags/mindless-stitchcraft/stitchmath"
+)
+
+// The search space doubles with each stitch, so keep it reasonable
+const MaxMotifLength = 16
+
+// Which generator to search
+type Pattern int
+
+const (
+	Zigzag Pattern = iota
+	Sync
+	Round
+)
+
+// All the patterns, in the order they are searched
+var AllPatterns = []Pattern{Zigzag, Sync, Round}
+
+func (pattern Pattern) String() string {
+	switch pattern {
+	case Zigzag:
+		return "zigzag"
+	case Sync:
+		return "sync"
+	default:
+		return "round"
+	}
+}
+
+// Parse a pattern name. "all" selects every pattern.
+func ParsePatterns(name string) ([]Pattern, error) {
+	switch name {
+	case "all":
+		return AllPatterns, nil
+	case "zigzag":
+		return []Pattern{Zigzag}, nil
+	case "sync":
+		return []Pattern{Sync}, nil
+	case "round":
+		return []Pattern{Round}, nil
+	default:
+		return nil, fmt.Errorf("pattern must be all, zigzag, sync or round, got %s", name)
+	}
+}
+
+// A motif and width that reproduce the chart
+type Solution struct {
+	Pattern Pattern
+	Motif   knitting.Motif
+	// Fabric width, or circumference for round patterns
+	Width int
+	// Height of one repeat of the generated fabric
+	Rows int
+}
+
+// Check that a chart only has knits and purls and that every row is the
+// same width
+func CheckChart(rows []string) error {
+	if len(rows) == 0 {
+		return errors.New("chart must not be empty")
+	}
+
+	width := len(rows[0])
+	for _, row := range rows {
+		motif, err := knitting.ParseMotif(row)
+		if err != nil {
+			return err
+		}
+
+		for _, stitch := range motif {
+			if stitch != knitting.Knit && stitch != knitting.Purl {
+				return fmt.Errorf("chart must only contain knits (v) and purls (-), got %s", row)
+			}
+		}
+
+		if len(motif) != width {
+			return errors.New("every row of the chart must have the same number of stitches")
+		}
+	}
+
+	return nil
+}
+
+// Motifs of knits and purls are enumerated as bit masks, with bits set for
+// purls. The first stitch is the most significant bit.
+func toMotif(mask uint32, length int) knitting.Motif {
+	result := make(knitting.Motif, length)
+	for i := range result {
+		if mask&(1<<(length-1-i)) != 0 {
+			result[i] = knitting.Purl
+		} else {
+			result[i] = knitting.Knit
+		}
+	}
+
+	return result
+}
+
+// A motif that is a shorter motif repeated (e.g. v-v-) makes the same
+// fabric as the shorter motif, which was already searched.
+func isPrimitive(mask uint32, length int) bool {
+	for period := 1; period < length; period++ {
+		if length%period != 0 {
+			continue
+		}
+
+		shifted := mask>>period | (mask&(1<<period-1))<<(length-period)
+		if shifted == mask {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Check if the mask is the smallest of its rotations
+func isFirstRotation(mask uint32, length int) bool {
+	rotated := mask
+	for i := 1; i < length; i++ {
+		rotated = rotated>>1 | (rotated&1)<<(length-1)
+		if rotated < mask {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Generate one repeat of the pattern as a chart, top row first
+func generate(pattern Pattern, motif knitting.Motif, width int) ([]string, error) {
+	switch pattern {
+	case Zigzag:
+		return zigzag.GenerateZigzagPattern(motif, width)
+	case Sync:
+		return knitsync.GeneratePattern(uint(width), []knitting.Motif{motif})
+	default:
+		rows, _, err := round.GenerateSpiralPattern(motif, width)
+		return rows, err
+	}
+}
+
+// Check if the fabric is the chart tiled in both directions, starting
+// anywhere in the chart. The fabric repeats vertically, so enough rows are
+// compared for both the fabric and the chart to repeat.
+func containsRepeat(fabric []string, chart []string) bool {
+	height := len(chart)
+	width := len(chart[0])
+	if len(fabric[0]) < width {
+		return false
+	}
+
+	rows := int(stitchmath.LCM(uint(len(fabric)), uint(height)))
+	for rowOffset := 0; rowOffset < height; rowOffset++ {
+		for columnOffset := 0; columnOffset < width; columnOffset++ {
+			if matches(fabric, chart, rows, rowOffset, columnOffset) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func matches(fabric []string, chart []string, rows int, rowOffset int, columnOffset int) bool {
+	for r := 0; r < rows; r++ {
+		fabricRow := fabric[r%len(fabric)]
+		chartRow := chart[(r+rowOffset)%len(chart)]
+		for c := 0; c < len(fabricRow); c++ {
+			if fabricRow[c] != chartRow[(c+columnOffset)%len(chartRow)] {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// Search for motifs of knits and purls and fabric widths whose generated
+// fabric is the chart repeated. The chart is listed from the top row to
+// the bottom row, and may start anywhere within the repeat.
+//
+// Motifs are tried from shortest to longest, and the search stops at the
+// first length with any solutions, so only the minimal solutions are
+// returned. For each pattern, widths are tried from the chart width up to
+// maxWidth and only the narrowest width that works is kept. If there are
+// no solutions with motifs up to maxLength stitches, the result is empty.
+func Solve(chart []string, patterns []Pattern, maxLength int, maxWidth int) ([]Solution, error) {
+	if err := CheckChart(chart); err != nil {
+		return nil, err
+	}
+
+	if maxLength < 1 || maxLength > MaxMotifLength {
+		return nil, fmt.Errorf("maxLength must be between 1 and %d", MaxMotifLength)
+	}
+
+	if maxWidth < len(chart[0]) {
+		return nil, errors.New("maxWidth must be at least the width of the chart")
+	}
+
+	for length := 1; length <= maxLength; length++ {
+		solutions := []Solution{}
+		for _, pattern := range patterns {
+			found, err := solveLength(chart, pattern, length, maxWidth)
+			if err != nil {
+				return nil, err
+			}
+			solutions = append(solutions, found...)
+		}
+
+		if len(solutions) > 0 {
+			sortSolutions(solutions)
+			return solutions, nil
+		}
+	}
+
+	return []Solution{}, nil
+}
+
+// Find the motifs of the given length that reproduce the chart with one
+// pattern, at the narrowest width that works
+func solveLength(chart []string, pattern Pattern, length int, maxWidth int) ([]Solution, error) {
+	for width := len(chart[0]); width <= maxWidth; width++ {
+		solutions := []Solution{}
+		for mask := uint32(0); mask < 1<<length; mask++ {
+			if !isPrimitive(mask, length) {
+				continue
+			}
+
+			// Rotating the motif of a round pattern only turns the tube,
+			// so only the first rotation is listed
+			if pattern == Round && !isFirstRotation(mask, length) {
+				continue
+			}
+
+			motif := toMotif(mask, length)
+			fabric, err := generate(pattern, motif, width)
+			if err != nil {
+				return nil, err
+			}
+
+			if containsRepeat(fabric, chart) {
+				solutions = append(solutions, Solution{pattern, motif, width, len(fabric)})
+			}
+		}
+
+		if len(solutions) > 0 {
+			return solutions, nil
+		}
+	}
+
+	return []Solution{}, nil
+}
+
+// Order solutions by pattern, then width, then motif
+func sortSolutions(solutions []Solution) {
+	sort.SliceStable(solutions, func(i, j int) bool {
+		a := solutions[i]
+		b := solutions[j]
+		if a.Pattern != b.Pattern {
+			return a.Pattern < b.Pattern
+		}
+
+		if a.Width != b.Width {
+			return a.Width < b.Width
+		}
+
+		return knitting.Row(a.Motif).ToString() < knitting.Row(b.Motif).ToString()
+	})
+}
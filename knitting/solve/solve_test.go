@@ -0,0 +1,95 @@
+package solve
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ptrgags/mindless-stitchcraft/checks"
+	"github.com/ptrgags/mindless-stitchcraft/knitting"
+)
+
+func describe(solutions []Solution) []string {
+	result := make([]string, len(solutions))
+	for i, solution := range solutions {
+		motif := knitting.Row(solution.Motif).ToString()
+		result[i] = fmt.Sprintf("%s %s %d", solution.Pattern, motif, solution.Width)
+	}
+
+	return result
+}
+
+func TestCheckChart(t *testing.T) {
+	t.Run("empty chart returns error", func(t *testing.T) {
+		err := CheckChart([]string{})
+
+		checks.CheckHasError(t, 0, err, "chart must not be empty")
+	})
+
+	t.Run("ragged chart returns error", func(t *testing.T) {
+		err := CheckChart([]string{"v-", "v"})
+
+		checks.CheckHasError(t, 0, err, "every row of the chart must have the same number of stitches")
+	})
+
+	t.Run("other stitches return error", func(t *testing.T) {
+		err := CheckChart([]string{"vo"})
+
+		checks.CheckHasError(t, 0, err, "chart must only contain knits (v) and purls (-), got vo")
+	})
+}
+
+func TestSolve(t *testing.T) {
+	t.Run("invalid maxLength returns error", func(t *testing.T) {
+		result, err := Solve([]string{"v-"}, AllPatterns, MaxMotifLength+1, 4)
+
+		checks.CheckHasError(t, result, err, "maxLength must be between 1 and 16")
+	})
+
+	t.Run("maxWidth narrower than the chart returns error", func(t *testing.T) {
+		result, err := Solve([]string{"vv--"}, AllPatterns, 4, 3)
+
+		checks.CheckHasError(t, result, err, "maxWidth must be at least the width of the chart")
+	})
+
+	t.Run("finds the zigzag that made a chart", func(t *testing.T) {
+		motif, _ := knitting.ParseMotif("v--")
+		fabric, _ := generate(Zigzag, motif, 9)
+
+		result, err := Solve(fabric, []Pattern{Zigzag}, 6, 18)
+
+		checks.CheckHasNoError(t, result, err)
+		// vv- makes the same fabric with knits and purls swapped, which
+		// is the same chart shifted by one row
+		checks.CheckSlicesEqual(t, describe(result), []string{"zigzag v-- 9", "zigzag vv- 9"})
+	})
+
+	t.Run("chart may start anywhere in the repeat", func(t *testing.T) {
+		chart := []string{"v-vv", "-vv-", "vv-v", "v-vv", "-vv-", "vv-v"}
+
+		result, err := Solve(chart, []Pattern{Round}, 6, 8)
+
+		checks.CheckHasNoError(t, result, err)
+		checks.CheckSlicesEqual(t, describe(result), []string{"round vv- 4"})
+	})
+
+	t.Run("round patterns list each motif once", func(t *testing.T) {
+		result, err := Solve([]string{"vv--", "v--v", "--vv", "-vv-"}, AllPatterns, 6, 8)
+
+		checks.CheckHasNoError(t, result, err)
+		checks.CheckSlicesEqual(t, describe(result), []string{"round vv-- 5"})
+	})
+
+	t.Run("only the shortest motifs are listed", func(t *testing.T) {
+		result, err := Solve([]string{"v-", "-v"}, AllPatterns, 6, 4)
+
+		checks.CheckHasNoError(t, result, err)
+		checks.CheckSlicesEqual(t, describe(result), []string{"sync -v 3", "sync v- 3", "round v- 3"})
+	})
+
+	t.Run("no solution returns empty list", func(t *testing.T) {
+		result, err := Solve([]string{"vv", "v-"}, AllPatterns, 3, 4)
+
+		checks.CheckHasNoError(t, result, err)
+		checks.CheckSliceEmpty(t, result)
+	})
+}
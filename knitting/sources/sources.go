@@ -0,0 +1,321 @@
+package sources
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/ptrgags/mindless-stitchcraft/knitting"
+)
+
+// Generated motifs are limited to this many stitches
+const MaxLength = 10000
+
+// The longest de Bruijn sequence has 2^MaxOrder stitches
+const MaxOrder = 13
+
+// A named sequence that can be turned into a motif. Sequences of 0s and 1s
+// become knits (0) and purls (1).
+type Source struct {
+	Name string
+	// How the parameters are written, e.g. "LENGTH"
+	Parameters string
+	// True if the sequence never repeats, so a motif made from it is
+	// only a prefix of the sequence
+	Aperiodic bool
+	generate  func(params []int) ([]int, error)
+}
+
+// All the sources, in the order they are listed in the help text
+var Sources = []Source{
+	{"fibonacci", "LENGTH", true, fibonacciWord},
+	{"thue-morse", "LENGTH", true, thueMorse},
+	{"binary", "N", false, binary},
+	{"pi", "LENGTH[,K]", true, piDigits},
+	{"debruijn", "ORDER", false, deBruijn},
+	{"runs", "RUN,RUN,...", false, runs},
+}
+
+func checkLength(length int) error {
+	if length < 1 || length > MaxLength {
+		return fmt.Errorf("length must be between 1 and %d", MaxLength)
+	}
+
+	return nil
+}
+
+// The Fibonacci word 0100101001001..., made by repeatedly replacing 0
+// with 01 and 1 with 0
+func fibonacciWord(params []int) ([]int, error) {
+	if len(params) != 1 {
+		return nil, errors.New("fibonacci takes a single length")
+	}
+
+	length := params[0]
+	if err := checkLength(length); err != nil {
+		return nil, err
+	}
+
+	word := []int{0}
+	for len(word) < length {
+		next := []int{}
+		for _, letter := range word {
+			if letter == 0 {
+				next = append(next, 0, 1)
+			} else {
+				next = append(next, 0)
+			}
+		}
+		word = next
+	}
+
+	return word[:length], nil
+}
+
+// The Thue-Morse sequence 0110100110010110..., where each term is the
+// number of 1 bits of its index, mod 2
+func thueMorse(params []int) ([]int, error) {
+	if len(params) != 1 {
+		return nil, errors.New("thue-morse takes a single length")
+	}
+
+	length := params[0]
+	if err := checkLength(length); err != nil {
+		return nil, err
+	}
+
+	result := make([]int, length)
+	for i := range result {
+		bits := 0
+		for n := i; n > 0; n >>= 1 {
+			bits += n & 1
+		}
+		result[i] = bits % 2
+	}
+
+	return result, nil
+}
+
+// The binary expansion of a positive integer, most significant bit first
+func binary(params []int) ([]int, error) {
+	if len(params) != 1 || params[0] < 1 {
+		return nil, errors.New("binary takes a single positive integer")
+	}
+
+	result := []int{}
+	for n := params[0]; n > 0; n >>= 1 {
+		result = append([]int{n & 1}, result...)
+	}
+
+	return result, nil
+}
+
+// The first digits of pi (3, 1, 4, 1, 5, ...) mod k, which defaults to 2.
+// Residues in the lower half (less than k/2, rounded up) become 0s and the
+// rest 1s, so every modulus gives a mix of knits and purls. Digits are
+// computed with Gibbons' unbounded spigot algorithm.
+func piDigits(params []int) ([]int, error) {
+	if len(params) < 1 || len(params) > 2 {
+		return nil, errors.New("pi takes a length and an optional modulus")
+	}
+
+	length := params[0]
+	if err := checkLength(length); err != nil {
+		return nil, err
+	}
+
+	modulus := 2
+	if len(params) == 2 {
+		modulus = params[1]
+	}
+	if modulus < 2 {
+		return nil, errors.New("the modulus for pi must be at least 2")
+	}
+
+	q := big.NewInt(1)
+	r := big.NewInt(0)
+	t := big.NewInt(1)
+	k := big.NewInt(1)
+	n := big.NewInt(3)
+	l := big.NewInt(3)
+
+	ten := big.NewInt(10)
+	temp := new(big.Int)
+	other := new(big.Int)
+
+	result := []int{}
+	for len(result) < length {
+		// The next digit is known once 4q + r - t < nt
+		temp.Mul(q, big.NewInt(4)).Add(temp, r).Sub(temp, t)
+		other.Mul(n, t)
+		if temp.Cmp(other) < 0 {
+			if int(n.Int64())%modulus < (modulus+1)/2 {
+				result = append(result, 0)
+			} else {
+				result = append(result, 1)
+			}
+
+			// n = 10(3q + r) / t - 10n
+			temp.Mul(q, big.NewInt(3)).Add(temp, r).Mul(temp, ten).Quo(temp, t)
+			other.Mul(n, ten)
+			newN := new(big.Int).Sub(temp, other)
+
+			// r = 10(r - nt)
+			temp.Mul(n, t)
+			r.Sub(r, temp).Mul(r, ten)
+			q.Mul(q, ten)
+			n = newN
+			continue
+		}
+
+		// n = (q(7k + 2) + rl) / (tl)
+		temp.Mul(k, big.NewInt(7)).Add(temp, big.NewInt(2)).Mul(temp, q)
+		other.Mul(r, l)
+		temp.Add(temp, other)
+		other.Mul(t, l)
+		newN := new(big.Int).Quo(temp, other)
+
+		// r = (2q + r)l
+		temp.Mul(q, big.NewInt(2))
+		r.Add(r, temp).Mul(r, l)
+		q.Mul(q, k)
+		t.Mul(t, l)
+		k.Add(k, big.NewInt(1))
+		l.Add(l, big.NewInt(2))
+		n = newN
+	}
+
+	return result, nil
+}
+
+// The binary de Bruijn sequence of the given order, which contains every
+// string of order 0s and 1s exactly once when read cyclically. This uses
+// the FKM algorithm, which joins the Lyndon words in order.
+func deBruijn(params []int) ([]int, error) {
+	if len(params) != 1 || params[0] < 1 || params[0] > MaxOrder {
+		return nil, fmt.Errorf("debruijn takes a single order between 1 and %d", MaxOrder)
+	}
+
+	order := params[0]
+	word := make([]int, order+1)
+	result := []int{}
+
+	var extend func(t int, p int)
+	extend = func(t int, p int) {
+		if t > order {
+			if order%p == 0 {
+				result = append(result, word[1:p+1]...)
+			}
+			return
+		}
+
+		word[t] = word[t-p]
+		extend(t+1, p)
+		for digit := word[t-p] + 1; digit < 2; digit++ {
+			word[t] = digit
+			extend(t+1, t)
+		}
+	}
+	extend(1, 1)
+
+	return result, nil
+}
+
+// Alternating runs of knits and purls with the given lengths, starting
+// with knits. E.g. 1,2,3 is v--vvv
+func runs(params []int) ([]int, error) {
+	if len(params) == 0 {
+		return nil, errors.New("runs takes at least one run length")
+	}
+
+	total := 0
+	for i, run := range params {
+		if run < 1 {
+			return nil, fmt.Errorf("run %d must be at least 1 stitch", i+1)
+		}
+
+		total += run
+		if total > MaxLength {
+			return nil, fmt.Errorf("runs must add up to at most %d stitches", MaxLength)
+		}
+	}
+
+	result := []int{}
+	for i, run := range params {
+		for j := 0; j < run; j++ {
+			result = append(result, i%2)
+		}
+	}
+
+	return result, nil
+}
+
+func toMotif(values []int) knitting.Motif {
+	result := make(knitting.Motif, len(values))
+	for i, value := range values {
+		if value == 0 {
+			result[i] = knitting.Knit
+		} else {
+			result[i] = knitting.Purl
+		}
+	}
+
+	return result
+}
+
+// Look up a source by the name before the colon, e.g. "pi:20". The second
+// return value is false if the text doesn't name a source, in which case
+// it is probably a motif written out by hand.
+func Lookup(text string) (Source, bool) {
+	name, _, found := strings.Cut(text, ":")
+	if !found {
+		return Source{}, false
+	}
+
+	for _, source := range Sources {
+		if source.Name == name {
+			return source, true
+		}
+	}
+
+	return Source{}, false
+}
+
+// Generate a motif from a source written as NAME:PARAMETERS, e.g.
+// "fibonacci:13", "pi:20,3" or "runs:1,2,3,2,1". Parameters are integers
+// separated by commas.
+func Parse(text string) (knitting.Motif, Source, error) {
+	source, ok := Lookup(text)
+	if !ok {
+		return nil, Source{}, fmt.Errorf("%s is not a motif source, must be one of %s", text, Names())
+	}
+
+	_, parameterText, _ := strings.Cut(text, ":")
+	params := []int{}
+	for _, field := range strings.Split(parameterText, ",") {
+		value, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil {
+			return nil, Source{}, fmt.Errorf("%s must be written as %s:%s", text, source.Name, source.Parameters)
+		}
+		params = append(params, value)
+	}
+
+	values, err := source.generate(params)
+	if err != nil {
+		return nil, Source{}, err
+	}
+
+	return toMotif(values), source, nil
+}
+
+// List the sources and their parameters, e.g. for error messages
+func Names() string {
+	names := make([]string, len(Sources))
+	for i, source := range Sources {
+		names[i] = source.Name + ":" + source.Parameters
+	}
+
+	return strings.Join(names, ", ")
+}
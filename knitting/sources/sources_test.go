@@ -0,0 +1,103 @@
+package sources
+
+import (
+	"testing"
+
+	"github.com/ptrgags/mindless-stitchcraft/checks"
+	"github.com/ptrgags/mindless-stitchcraft/knitting"
+)
+
+func TestParse(t *testing.T) {
+	t.Run("generates motifs from each source", func(t *testing.T) {
+		cases := []struct {
+			text      string
+			expected  string
+			aperiodic bool
+		}{
+			{"fibonacci:13", "v-vv-v-vv-vv-", true},
+			{"thue-morse:16", "v--v-vv--vv-v--v", true},
+			{"binary:37", "-vv-v-", false},
+			// 3 1 4 1 5 9 2 6 5 3
+			{"pi:10", "--v---vv--", true},
+			// 3 1 4 1 5 9 2 6 5 3 mod 3 is 0 1 1 1 2 0 2 0 2 0
+			{"pi:10,3", "vvvv-v-v-v", true},
+			{"debruijn:3", "vvv-v---", false},
+			{"runs:1,2,3,2,1", "v--vvv--v", false},
+		}
+
+		for _, tc := range cases {
+			t.Run(tc.text, func(t *testing.T) {
+				motif, source, err := Parse(tc.text)
+
+				checks.CheckHasNoError(t, motif, err)
+				if knitting.Row(motif).ToString() != tc.expected {
+					t.Errorf("expected %s, got %s", tc.expected, knitting.Row(motif).ToString())
+				}
+				if source.Aperiodic != tc.aperiodic {
+					t.Errorf("expected aperiodic to be %v", tc.aperiodic)
+				}
+			})
+		}
+	})
+
+	t.Run("unknown source returns error", func(t *testing.T) {
+		motif, _, err := Parse("primes:10")
+
+		checks.CheckHasError(t, motif, err, "primes:10 is not a motif source, must be one of fibonacci:LENGTH, thue-morse:LENGTH, binary:N, pi:LENGTH[,K], debruijn:ORDER, runs:RUN,RUN,...")
+	})
+
+	t.Run("invalid parameters return error", func(t *testing.T) {
+		cases := []struct {
+			text     string
+			expected string
+		}{
+			{"fibonacci:abc", "fibonacci:abc must be written as fibonacci:LENGTH"},
+			{"fibonacci:0", "length must be between 1 and 10000"},
+			{"thue-morse:4,5", "thue-morse takes a single length"},
+			{"binary:0", "binary takes a single positive integer"},
+			{"pi:10,1", "the modulus for pi must be at least 2"},
+			{"debruijn:14", "debruijn takes a single order between 1 and 13"},
+			{"runs:1,0,2", "run 2 must be at least 1 stitch"},
+			{"runs:6000,6000", "runs must add up to at most 10000 stitches"},
+		}
+
+		for _, tc := range cases {
+			t.Run(tc.text, func(t *testing.T) {
+				motif, _, err := Parse(tc.text)
+
+				checks.CheckHasError(t, motif, err, tc.expected)
+			})
+		}
+	})
+}
+
+func TestDeBruijn(t *testing.T) {
+	// Every string of 4 stitches appears exactly once, reading cyclically
+	values, err := deBruijn([]int{4})
+
+	checks.CheckHasNoError(t, values, err)
+	seen := map[int]bool{}
+	for i := range values {
+		window := 0
+		for j := 0; j < 4; j++ {
+			window = window*2 + values[(i+j)%len(values)]
+		}
+		seen[window] = true
+	}
+
+	if len(values) != 16 || len(seen) != 16 {
+		t.Errorf("expected all 16 windows in 16 stitches, got %d windows in %v", len(seen), values)
+	}
+}
+
+func TestLookup(t *testing.T) {
+	source, ok := Lookup("pi:20")
+	if !ok || source.Name != "pi" {
+		t.Errorf("expected the pi source, got %v", source)
+	}
+
+	_, ok = Lookup("vv--")
+	if ok {
+		t.Errorf("expected a motif without a colon to not be a source")
+	}
+}
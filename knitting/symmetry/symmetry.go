@@ -0,0 +1,454 @@
+package symmetry
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ptrgags/mindless-stitchcraft/knitting"
+	"github.com/ptrgags/mindless-stitchcraft/stitchmath"
+)
+
+// The linear part of a symmetry of the plane. Only the isometries that
+// map a rectangular grid of stitches to itself are considered, so there
+// are no 90 degree rotations or diagonal mirrors.
+type Kind int
+
+const (
+	Translation Kind = iota
+	Rotation180
+	// Mirror across a vertical axis, swapping left and right
+	MirrorLeftRight
+	// Mirror across a horizontal axis, swapping top and bottom
+	MirrorTopBottom
+)
+
+// A transformation that maps the fabric onto itself.
+type Transform struct {
+	Kind Kind
+	// Whether knits and purls are swapped as well
+	Swap bool
+	// The translation applied after the linear part, measured in stitches
+	// to the right and rows up
+	DX int
+	DY int
+	// For mirrors, whether this is a glide reflection, i.e. the
+	// reflection must be combined with a translation along the mirror
+	// axis.
+	Glide bool
+}
+
+func (transform Transform) String() string {
+	var description string
+	switch transform.Kind {
+	case Translation:
+		description = "translation by " + describeOffset(transform.DX, transform.DY)
+	case Rotation180:
+		description = "180° rotation"
+	case MirrorLeftRight:
+		if transform.Glide {
+			description = fmt.Sprintf("glide reflection left-right (%d row(s))", transform.DY)
+		} else {
+			description = "mirror left-right"
+		}
+	case MirrorTopBottom:
+		if transform.Glide {
+			description = fmt.Sprintf("glide reflection top-bottom (%d stitch(es))", transform.DX)
+		} else {
+			description = "mirror top-bottom"
+		}
+	}
+
+	if transform.Swap {
+		if transform.Kind == Translation && transform.DX == 0 && transform.DY == 0 {
+			return "knit/purl swap"
+		}
+		return "knit/purl swap + " + description
+	}
+
+	return description
+}
+
+// Describe a translation, leaving out a direction with no offset
+func describeOffset(dx int, dy int) string {
+	switch {
+	case dx == 0:
+		return fmt.Sprintf("%d row(s)", dy)
+	case dy == 0:
+		return fmt.Sprintf("%d stitch(es)", dx)
+	default:
+		return fmt.Sprintf("%d stitch(es) and %d row(s)", dx, dy)
+	}
+}
+
+// The result of classifying the symmetry of a fabric.
+type Report struct {
+	// The wallpaper group (e.g. "pmg"), or the frieze group for
+	// single-row bands (e.g. "p11m"), in IUC notation
+	Group string
+	// True if Group is a frieze group
+	Frieze bool
+	// Size of the periodic tile that was analyzed
+	TileWidth  int
+	TileHeight int
+	// One representative of each type of transform that maps the fabric
+	// onto itself. The identity is not included.
+	Transforms []Transform
+}
+
+// A fabric is reversible if the back looks like the front, i.e. some
+// transform combined with a knit/purl swap maps the fabric onto itself.
+func (report Report) Reversible() bool {
+	for _, transform := range report.Transforms {
+		if transform.Swap {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (report Report) String() string {
+	kind := "wallpaper group"
+	if report.Frieze {
+		kind = "frieze group"
+	}
+
+	descriptions := make([]string, len(report.Transforms))
+	for i, transform := range report.Transforms {
+		descriptions[i] = transform.String()
+	}
+
+	transforms := "none"
+	if len(descriptions) > 0 {
+		transforms = strings.Join(descriptions, "; ")
+	}
+
+	reversible := "no"
+	if report.Reversible() {
+		reversible = "yes"
+	}
+
+	return fmt.Sprintf("Symmetry: %s %s, reversible: %s, transforms: %s", kind, report.Group, reversible, transforms)
+}
+
+// Find the shortest period of a row, i.e. the smallest p such that
+// stitch i matches stitch i + p. Periods must repeat at least twice to be
+// trusted, otherwise the whole row is the period.
+func rowPeriod(row knitting.Row) int {
+	n := len(row)
+	for p := 1; 2*p <= n; p++ {
+		matches := true
+		for i := 0; i+p < n; i++ {
+			if row[i] != row[i+p] {
+				matches = false
+				break
+			}
+		}
+
+		if matches {
+			return p
+		}
+	}
+
+	return n
+}
+
+// Treat the fabric as a tile that repeats in both directions. The fabric
+// is assumed to be a full vertical repeat, and each row is extended
+// horizontally using its own period. If the rows don't line up within the
+// width of the fabric, the fabric itself is used as the tile so the search
+// stays small.
+type tile struct {
+	width   int
+	height  int
+	columns [][]knitting.KnitStitch
+}
+
+func makeTile(fabric knitting.Fabric) tile {
+	fabricWidth := uint(len(fabric[0]))
+	width := uint(1)
+	for _, row := range fabric {
+		width = stitchmath.LCM(width, uint(rowPeriod(row)))
+		if width > fabricWidth {
+			width = fabricWidth
+			break
+		}
+	}
+
+	height := len(fabric)
+	columns := make([][]knitting.KnitStitch, height)
+	for y, row := range fabric {
+		columns[y] = make([]knitting.KnitStitch, width)
+		period := rowPeriod(row)
+		for x := range columns[y] {
+			columns[y][x] = row[x%period]
+		}
+	}
+
+	return tile{int(width), height, columns}
+}
+
+func mod(a int, n int) int {
+	return ((a % n) + n) % n
+}
+
+// Look up a stitch, wrapping around the edges of the tile. y is measured
+// in rows up from the bottom of the chart.
+func (t tile) at(x int, y int) knitting.KnitStitch {
+	row := t.height - 1 - mod(y, t.height)
+	return t.columns[row][mod(x, t.width)]
+}
+
+// Apply the linear part of the transform to a point
+func apply(kind Kind, x int, y int) (int, int) {
+	switch kind {
+	case Rotation180:
+		return -x, -y
+	case MirrorLeftRight:
+		return -x, y
+	case MirrorTopBottom:
+		return x, -y
+	default:
+		return x, y
+	}
+}
+
+// Check if the transform maps every stitch of the tile to an identical
+// stitch (or swapped stitch)
+func (t tile) isSymmetry(kind Kind, swap bool, dx int, dy int) bool {
+	for y := 0; y < t.height; y++ {
+		for x := 0; x < t.width; x++ {
+			tx, ty := apply(kind, x, y)
+			expected := t.at(x, y)
+			if swap {
+				expected = expected.Swap()
+			}
+
+			if t.at(tx+dx, ty+dy) != expected {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// Express an offset within the tile as the shortest signed distance
+func signed(offset int, size int) int {
+	if 2*offset > size {
+		return offset - size
+	}
+	return offset
+}
+
+// Find all offsets (dx, dy) that make the given transform a symmetry
+func (t tile) findOffsets(kind Kind, swap bool) [][2]int {
+	offsets := [][2]int{}
+	for dy := 0; dy < t.height; dy++ {
+		for dx := 0; dx < t.width; dx++ {
+			if t.isSymmetry(kind, swap, dx, dy) {
+				offsets = append(offsets, [2]int{dx, dy})
+			}
+		}
+	}
+
+	return offsets
+}
+
+func contains(offsets [][2]int, offset [2]int) bool {
+	for _, candidate := range offsets {
+		if candidate == offset {
+			return true
+		}
+	}
+	return false
+}
+
+// Which mirrors and glide reflections exist for one mirror direction
+type reflections struct {
+	mirror          bool
+	glide           bool
+	mirrorTransform Transform
+	glideTransform  Transform
+}
+
+// Sort out which reflections are true mirrors and which are glides. A
+// reflection combined with a translation along its axis is still a
+// mirror if that translation is a symmetry on its own.
+func (t tile) findReflections(kind Kind, swap bool, translations [][2]int) reflections {
+	result := reflections{}
+	for _, offset := range t.findOffsets(kind, swap) {
+		// The component of the offset that runs along the mirror axis
+		along := [2]int{0, offset[1]}
+		if kind == MirrorTopBottom {
+			along = [2]int{offset[0], 0}
+		}
+
+		transform := Transform{
+			Kind: kind,
+			Swap: swap,
+			DX:   signed(offset[0], t.width),
+			DY:   signed(offset[1], t.height),
+		}
+
+		if along == [2]int{0, 0} || contains(translations, along) {
+			if !result.mirror {
+				result.mirror = true
+				result.mirrorTransform = transform
+			}
+		} else if !result.glide {
+			transform.Glide = true
+			result.glide = true
+			result.glideTransform = transform
+		}
+	}
+
+	return result
+}
+
+func (r reflections) any() bool {
+	return r.mirror || r.glide
+}
+
+func (r reflections) transforms() []Transform {
+	result := []Transform{}
+	if r.mirror {
+		result = append(result, r.mirrorTransform)
+	}
+	if r.glide {
+		result = append(result, r.glideTransform)
+	}
+	return result
+}
+
+// Name the wallpaper group from which rotations and reflections exist.
+func wallpaperGroup(rotation bool, leftRight reflections, topBottom reflections) string {
+	if !leftRight.any() && !topBottom.any() {
+		if rotation {
+			return "p2"
+		}
+		return "p1"
+	}
+
+	if leftRight.any() != topBottom.any() {
+		single := leftRight
+		if topBottom.any() {
+			single = topBottom
+		}
+
+		switch {
+		case single.mirror && single.glide:
+			return "cm"
+		case single.mirror:
+			return "pm"
+		default:
+			return "pg"
+		}
+	}
+
+	switch {
+	case leftRight.mirror && topBottom.mirror:
+		if leftRight.glide || topBottom.glide {
+			return "cmm"
+		}
+		return "pmm"
+	case leftRight.mirror || topBottom.mirror:
+		return "pmg"
+	default:
+		return "pgg"
+	}
+}
+
+// Name the frieze group of a single row band. A single row is always
+// symmetric top to bottom, so only the left-right mirror matters.
+func friezeGroup(leftRight reflections) string {
+	if leftRight.mirror {
+		return "p2mm"
+	}
+	return "p11m"
+}
+
+// Collect one representative transform of each type, optionally combined
+// with a knit/purl swap.
+func (t tile) collectTransforms(swap bool, translations [][2]int) ([]Transform, bool, reflections, reflections) {
+	result := []Transform{}
+
+	for _, offset := range t.findOffsets(Translation, swap) {
+		if !swap && offset == [2]int{0, 0} {
+			continue
+		}
+
+		result = append(result, Transform{
+			Kind: Translation,
+			Swap: swap,
+			DX:   signed(offset[0], t.width),
+			DY:   signed(offset[1], t.height),
+		})
+		break
+	}
+
+	rotations := t.findOffsets(Rotation180, swap)
+	rotation := len(rotations) > 0
+	if rotation {
+		result = append(result, Transform{
+			Kind: Rotation180,
+			Swap: swap,
+			DX:   signed(rotations[0][0], t.width),
+			DY:   signed(rotations[0][1], t.height),
+		})
+	}
+
+	leftRight := t.findReflections(MirrorLeftRight, swap, translations)
+	topBottom := t.findReflections(MirrorTopBottom, swap, translations)
+	result = append(result, leftRight.transforms()...)
+	result = append(result, topBottom.transforms()...)
+
+	return result, rotation, leftRight, topBottom
+}
+
+// Classify the symmetry of a fabric chart. The chart is treated as a
+// periodic tile: the rows are one full vertical repeat, and each row
+// repeats horizontally with its own period. The tile is never wider than
+// the chart.
+//
+// For charts with a single row, the fabric is treated as a band and the
+// frieze group is reported instead of the wallpaper group.
+func Classify(fabric knitting.Fabric) (Report, error) {
+	if len(fabric) == 0 || len(fabric[0]) == 0 {
+		return Report{}, errors.New("fabric must not be empty")
+	}
+
+	for _, row := range fabric {
+		if len(row) != len(fabric[0]) {
+			return Report{}, errors.New("all rows of the fabric must have the same width")
+		}
+	}
+
+	t := makeTile(fabric)
+	translations := [][2]int{}
+	for _, offset := range t.findOffsets(Translation, false) {
+		if offset != [2]int{0, 0} {
+			translations = append(translations, offset)
+		}
+	}
+
+	transforms, rotation, leftRight, topBottom := t.collectTransforms(false, translations)
+	swapped, _, _, _ := t.collectTransforms(true, translations)
+	transforms = append(transforms, swapped...)
+
+	report := Report{
+		TileWidth:  t.width,
+		TileHeight: t.height,
+		Transforms: transforms,
+	}
+
+	if t.height == 1 {
+		report.Frieze = true
+		report.Group = friezeGroup(leftRight)
+	} else {
+		report.Group = wallpaperGroup(rotation, leftRight, topBottom)
+	}
+
+	return report, nil
+}
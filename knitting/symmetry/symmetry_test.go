@@ -0,0 +1,192 @@
+package symmetry
+
+import (
+	"testing"
+
+	"github.com/ptrgags/mindless-stitchcraft/checks"
+	"github.com/ptrgags/mindless-stitchcraft/knitting"
+)
+
+func parseChart(t *testing.T, rows []string) knitting.Fabric {
+	fabric := make(knitting.Fabric, len(rows))
+	for i, row := range rows {
+		motif, err := knitting.ParseMotif(row)
+		if err != nil {
+			t.Fatalf("invalid chart row %s: %v", row, err)
+		}
+		fabric[i] = knitting.Row(motif)
+	}
+
+	return fabric
+}
+
+func hasTransform(report Report, kind Kind, swap bool, glide bool) bool {
+	for _, transform := range report.Transforms {
+		if transform.Kind == kind && transform.Swap == swap && transform.Glide == glide {
+			return true
+		}
+	}
+
+	return false
+}
+
+func TestClassify(t *testing.T) {
+	t.Run("empty fabric returns error", func(t *testing.T) {
+		result, err := Classify(knitting.Fabric{})
+
+		checks.CheckHasError(t, result, err, "fabric must not be empty")
+	})
+
+	t.Run("ragged fabric returns error", func(t *testing.T) {
+		fabric := parseChart(t, []string{"v-v", "v-"})
+
+		result, err := Classify(fabric)
+
+		checks.CheckHasError(t, result, err, "all rows of the fabric must have the same width")
+	})
+
+	t.Run("classifies wallpaper groups", func(t *testing.T) {
+		cases := []struct {
+			label    string
+			rows     []string
+			expected string
+		}{
+			{"asymmetric tile", []string{"vv-", "v--", "---"}, "p1"},
+			{"diagonal stripes", []string{"v--", "-v-", "--v"}, "p2"},
+			{"mirror only", []string{"vv-", "v--"}, "pm"},
+			{"mirror and glide", []string{"v--", "-v-"}, "pmg"},
+			{"horizontal stripes", []string{"vvvv", "----"}, "pmm"},
+			{"checkerboard", []string{"v-", "-v"}, "cmm"},
+		}
+
+		for _, tc := range cases {
+			t.Run(tc.label, func(t *testing.T) {
+				fabric := parseChart(t, tc.rows)
+
+				result, err := Classify(fabric)
+
+				checks.CheckHasNoError(t, result, err)
+				if result.Frieze {
+					t.Errorf("expected a wallpaper group, got frieze group")
+				}
+				if result.Group != tc.expected {
+					t.Errorf("expected %s, got %s", tc.expected, result.Group)
+				}
+			})
+		}
+	})
+
+	t.Run("single row uses frieze group", func(t *testing.T) {
+		cases := []struct {
+			label    string
+			row      string
+			expected string
+		}{
+			{"symmetric row", "vv-vv-", "p2mm"},
+			{"asymmetric row", "vv-v--", "p11m"},
+		}
+
+		for _, tc := range cases {
+			t.Run(tc.label, func(t *testing.T) {
+				fabric := parseChart(t, []string{tc.row})
+
+				result, err := Classify(fabric)
+
+				checks.CheckHasNoError(t, result, err)
+				if !result.Frieze {
+					t.Errorf("expected a frieze group")
+				}
+				if result.Group != tc.expected {
+					t.Errorf("expected %s, got %s", tc.expected, result.Group)
+				}
+			})
+		}
+	})
+
+	t.Run("tile is extended to the least common multiple of row periods", func(t *testing.T) {
+		fabric := parseChart(t, []string{"v-v-v-", "vv-vv-"})
+
+		result, err := Classify(fabric)
+
+		checks.CheckHasNoError(t, result, err)
+		if result.TileWidth != 6 || result.TileHeight != 2 {
+			t.Errorf("expected a 6x2 tile, got %dx%d", result.TileWidth, result.TileHeight)
+		}
+	})
+
+	t.Run("tile is never wider than the chart", func(t *testing.T) {
+		fabric := parseChart(t, []string{"vvv-vvv-v", "v--v--v--"})
+
+		result, err := Classify(fabric)
+
+		checks.CheckHasNoError(t, result, err)
+		if result.TileWidth != 9 || result.TileHeight != 2 {
+			t.Errorf("expected a 9x2 tile, got %dx%d", result.TileWidth, result.TileHeight)
+		}
+	})
+
+	t.Run("checkerboard has glides and is reversible", func(t *testing.T) {
+		fabric := parseChart(t, []string{"v-", "-v"})
+
+		result, _ := Classify(fabric)
+
+		if !hasTransform(result, MirrorLeftRight, false, true) {
+			t.Errorf("expected a left-right glide reflection")
+		}
+		if !hasTransform(result, Translation, true, false) {
+			t.Errorf("expected a translation with knit/purl swap")
+		}
+		if !result.Reversible() {
+			t.Errorf("expected checkerboard to be reversible")
+		}
+	})
+
+	t.Run("stockinette-heavy fabric is not reversible", func(t *testing.T) {
+		fabric := parseChart(t, []string{"v-v", "vvv"})
+
+		result, _ := Classify(fabric)
+
+		if result.Reversible() {
+			t.Errorf("expected fabric not to be reversible")
+		}
+		if hasTransform(result, Translation, false, false) {
+			t.Errorf("expected no translations within the tile")
+		}
+	})
+}
+
+func TestTransformString(t *testing.T) {
+	cases := []struct {
+		transform Transform
+		expected  string
+	}{
+		{Transform{Kind: Translation, DY: 1}, "translation by 1 row(s)"},
+		{Transform{Kind: Translation, DX: 3}, "translation by 3 stitch(es)"},
+		{Transform{Kind: Translation, DX: 3, DY: -2}, "translation by 3 stitch(es) and -2 row(s)"},
+		{Transform{Kind: Translation, Swap: true}, "knit/purl swap"},
+		{Transform{Kind: MirrorLeftRight, Swap: true, DY: 1, Glide: true}, "knit/purl swap + glide reflection left-right (1 row(s))"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.expected, func(t *testing.T) {
+			if tc.transform.String() != tc.expected {
+				t.Errorf("expected %s, got %s", tc.expected, tc.transform.String())
+			}
+		})
+	}
+}
+
+func TestReportString(t *testing.T) {
+	report := Report{
+		Group: "pm",
+		Transforms: []Transform{
+			{Kind: MirrorTopBottom},
+			{Kind: MirrorLeftRight, Swap: true, DY: 1, Glide: true},
+		},
+	}
+
+	expected := "Symmetry: wallpaper group pm, reversible: yes, transforms: mirror top-bottom; knit/purl swap + glide reflection left-right (1 row(s))"
+	if report.String() != expected {
+		t.Errorf("expected %s, got %s", expected, report.String())
+	}
+}
@@ -4,33 +4,56 @@ import (
 	"errors"
 
 	"github.com/ptrgags/mindless-stitchcraft/knitting"
+	"github.com/ptrgags/mindless-stitchcraft/knitting/phase"
 )
 
-func GeneratePattern(fabricWidth uint, motifs []knitting.Motif) ([]string, error) {
+// Generate the sync pattern as the rows are worked by the knitter, from
+// the first row to the last. Every row starts at the beginning of the
+// next motif, see phase.Restart. Every second row is worked on the wrong
+// side of the fabric.
+//
+// Motifs with increases or decreases are rejected if any row would end
+// up with a different number of stitches than fabricWidth.
+func GenerateStitchingOrder(fabricWidth uint, motifs []knitting.Motif) (knitting.Fabric, error) {
 	if fabricWidth < 1 {
-		return []string{}, errors.New("fabricWidth must be positive")
+		return knitting.Fabric{}, errors.New("fabricWidth must be positive")
 	}
 
-	length := len(motifs)
-	if length < 1 {
-		return []string{}, errors.New("motifs must be non-empty")
+	if len(motifs) < 1 {
+		return knitting.Fabric{}, errors.New("motifs must be non-empty")
 	}
 
-	if length%2 == 1 {
-		length *= 2
+	fabric, _, err := phase.Generate(int(fabricWidth), motifs, phase.Restart)
+	if err != nil {
+		return knitting.Fabric{}, err
 	}
 
-	fabric := make(knitting.Fabric, length)
-	for i := 0; i < length; i++ {
-		motif := motifs[i%len(motifs)]
-		row := knitting.Row(motif.RepeatToLength(fabricWidth))
+	return fabric, nil
+}
+
+// Generate the sync pattern as a chart of the front of the fabric.
+// The first row knit is at the bottom of the chart.
+func GenerateFabric(fabricWidth uint, motifs []knitting.Motif) (knitting.Fabric, error) {
+	fabric, err := GenerateStitchingOrder(fabricWidth, motifs)
+	if err != nil {
+		return knitting.Fabric{}, err
+	}
 
-		if i%2 == 1 {
-			row = row.Reverse().SwapKnitsAndPurls()
-		}
+	return fabric.HandleReverseRows().Rotate180(), nil
+}
 
-		fabric[i] = row
+func GeneratePattern(fabricWidth uint, motifs []knitting.Motif) ([]string, error) {
+	fabric, err := GenerateFabric(fabricWidth, motifs)
+	if err != nil {
+		return []string{}, err
 	}
 
-	return fabric.Rotate180().ToStrings(), nil
+	return fabric.ToStrings(), nil
+}
+
+// Generate a shaped sync pattern where each row has its own width, as the
+// rows are worked by the knitter. Every row starts at the beginning of the
+// next motif.
+func GenerateShaped(widths []int, motifs []knitting.Motif) (knitting.Fabric, []phase.Phase, error) {
+	return phase.GenerateShaped(widths, motifs, phase.Restart)
 }
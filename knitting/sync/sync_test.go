@@ -52,4 +52,32 @@ func TestGeneratePattern(t *testing.T) {
 		checks.CheckHasNoError(t, result, err)
 		checks.CheckSlicesEqual(t, result, expectedPattern)
 	})
+	t.Run("motif that changes the row width results in error", func(t *testing.T) {
+		fabricWidth := uint(4)
+		motif, _ := knitting.ParseMotif("vo")
+
+		result, err := GeneratePattern(fabricWidth, []knitting.Motif{motif})
+
+		checks.CheckHasError(t, result, err, "row 1 would change the row width")
+	})
+}
+
+func TestGenerateShaped(t *testing.T) {
+	t.Run("each row restarts the next motif", func(t *testing.T) {
+		first, _ := knitting.ParseMotif("v-")
+		second, _ := knitting.ParseMotif("vv-")
+
+		result, _, err := GenerateShaped([]int{2, 4, 6}, []knitting.Motif{first, second})
+
+		checks.CheckHasNoError(t, result, err)
+		checks.CheckSlicesEqual(t, result.ToStrings(), []string{"v-", "vv-v", "v-v-v-"})
+	})
+
+	t.Run("motif that changes the row width results in error", func(t *testing.T) {
+		motif, _ := knitting.ParseMotif("vo")
+
+		result, _, err := GenerateShaped([]int{2, 4}, []knitting.Motif{motif})
+
+		checks.CheckHasError(t, result, err, "row 1 would change the row width: it works 1 stitches and leaves 2, but the row is 2 stitches wide")
+	})
 }
@@ -0,0 +1,70 @@
+package zigzag
+
+import (
+	"errors"
+
+	"github.com/ptrgags/mindless-stitchcraft/stitchmath"
+)
+
+// Summary of a zigzag pattern that can be computed from the motif length
+// and fabric width alone, without generating the fabric.
+type Analysis struct {
+	// How many rows until the pattern repeats vertically. This is always
+	// even so the knitter ends up back on the right side of the fabric.
+	RowRepeat int
+	// How many stitches until each row repeats horizontally. This is the
+	// motif length, which may be wider than the fabric itself.
+	StitchRepeat int
+	// How many stitches the motif moves every two rows, reading the chart
+	// from bottom to top. Positive values move to the right, negative
+	// values move to the left, and 0 means the motif stacks vertically.
+	Slope int
+	// How many complete copies of the motif are knit in one full repeat
+	MotifsPerRepeat int
+}
+
+// Analyze a zigzag pattern of a motif with motifLength stitches on a
+// fabric fabricWidth stitches wide. This matches the output of
+// GenerateZigzagPattern.
+//
+// Every row continues where the previous one left off, so the pattern
+// repeats once a whole number of motifs fits in a whole number of rows,
+// i.e. after lcm(motifLength, fabricWidth) stitches. Each pair of rows
+// advances 2 * fabricWidth stitches through the motif, which causes
+// diagonal lines.
+func AnalyzePattern(motifLength int, fabricWidth int) (Analysis, error) {
+	if fabricWidth < 1 {
+		return Analysis{}, errors.New("fabricWidth must be a positive integer")
+	}
+
+	if motifLength < 1 {
+		return Analysis{}, errors.New("motifLength must be a positive integer")
+	}
+
+	m := uint(motifLength)
+	w := uint(fabricWidth)
+
+	stitchesPerRepeat := stitchmath.LCM(m, w)
+	rows := int(stitchesPerRepeat / w)
+	motifs := int(stitchesPerRepeat / m)
+
+	// Match phase.Generate, which always ends on an even number of rows
+	if rows%2 == 1 {
+		rows *= 2
+		motifs *= 2
+	}
+
+	// Choose the shortest way around the motif so the slope describes
+	// the most visible diagonal.
+	slope := (2 * fabricWidth) % motifLength
+	if 2*slope > motifLength {
+		slope -= motifLength
+	}
+
+	return Analysis{
+		RowRepeat:       rows,
+		StitchRepeat:    motifLength,
+		Slope:           slope,
+		MotifsPerRepeat: motifs,
+	}, nil
+}
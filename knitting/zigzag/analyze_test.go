@@ -0,0 +1,85 @@
+package zigzag
+
+import (
+	"testing"
+
+	"github.com/ptrgags/mindless-stitchcraft/checks"
+	"github.com/ptrgags/mindless-stitchcraft/knitting"
+)
+
+func TestAnalyzePattern(t *testing.T) {
+	t.Run("invalid fabricWidth returns error", func(t *testing.T) {
+		result, err := AnalyzePattern(3, 0)
+
+		checks.CheckHasError(t, result, err, "fabricWidth must be a positive integer")
+	})
+
+	t.Run("invalid motifLength returns error", func(t *testing.T) {
+		result, err := AnalyzePattern(0, 10)
+
+		checks.CheckHasError(t, result, err, "motifLength must be a positive integer")
+	})
+
+	t.Run("row repeat matches generated pattern", func(t *testing.T) {
+		cases := []struct {
+			label       string
+			motif       string
+			fabricWidth int
+		}{
+			{"len(motif) == fabricWidth", "v--", 3},
+			{"len(motif) < fabricWidth, coprime widths", "v---", 5},
+			{"len(motif) < fabricWidth, noncoprime widths", "---vv-", 9},
+			{"len(motif) > fabricWidth, coprime widths", "-vvv-", 3},
+			{"len(motif) > fabricWidth, noncoprime widths", "--v---vvv", 3},
+		}
+
+		for _, tc := range cases {
+			t.Run(tc.label, func(t *testing.T) {
+				motif, _ := knitting.ParseMotif(tc.motif)
+				rows, _ := GenerateZigzagPattern(motif, tc.fabricWidth)
+
+				result, err := AnalyzePattern(len(motif), tc.fabricWidth)
+
+				checks.CheckHasNoError(t, result, err)
+				if result.RowRepeat != len(rows) {
+					t.Errorf("Expected %v rows, got %v", len(rows), result.RowRepeat)
+				}
+			})
+		}
+	})
+
+	t.Run("computes the full analysis", func(t *testing.T) {
+		cases := []struct {
+			label       string
+			motifLength int
+			fabricWidth int
+			expected    Analysis
+		}{
+			// lcm(3, 10) = 30 = 3 rows = 10 motifs, doubled to be even.
+			// 20 % 3 = 2, which is the same as moving 1 to the left
+			{"odd row count is doubled", 3, 10, Analysis{6, 3, -1, 20}},
+			// lcm(4, 5) = 20 = 4 rows = 5 motifs
+			// 10 % 4 = 2, halfway around the motif
+			{"even row count", 4, 5, Analysis{4, 4, 2, 5}},
+			// lcm(5, 7) = 35 = 5 rows = 7 motifs, doubled to be even.
+			// 14 % 5 = 4, which is the same as moving 1 to the left
+			{"slope to the left", 5, 7, Analysis{10, 5, -1, 14}},
+			// lcm(5, 8) = 40 = 5 rows = 8 motifs, doubled to be even.
+			// 16 % 5 = 1
+			{"slope to the right", 5, 8, Analysis{10, 5, 1, 16}},
+			// lcm(3, 9) = 9 = 1 row = 3 motifs, doubled to be even
+			{"stacked motifs", 3, 9, Analysis{2, 3, 0, 6}},
+		}
+
+		for _, tc := range cases {
+			t.Run(tc.label, func(t *testing.T) {
+				result, err := AnalyzePattern(tc.motifLength, tc.fabricWidth)
+
+				checks.CheckHasNoError(t, result, err)
+				if result != tc.expected {
+					t.Errorf("Expected %+v, got %+v", tc.expected, result)
+				}
+			})
+		}
+	})
+}
@@ -2,114 +2,172 @@ package zigzag
 
 import (
 	"errors"
+	"fmt"
+	"sort"
 
 	"github.com/ptrgags/mindless-stitchcraft/knitting"
+	"github.com/ptrgags/mindless-stitchcraft/knitting/phase"
 )
 
-// Given a row of the fabric with a fixed width, fill it with copies of motif
-// plus a substring that went past the end of the last row by overhang stitches.
+// The motifs of a sequence, each repeated switchEvery times, may add up to
+// at most this many stitches
+const MaxSequenceLength = 10000
+
+// Generate the zigzag pattern as the rows are worked by the knitter, from
+// the first row to the last. Every row continues the motif where the
+// previous row ended, see phase.Continue. Every second row is worked on the
+// wrong side of the fabric.
 //
-// This returns (row, overhang) for this row.
-func fillRow(overhang int, motif knitting.Motif, fabricWidth int) (knitting.Row, int) {
+// Motifs with increases or decreases are rejected if any row would end
+// up with a different number of stitches than fabricWidth.
+func GenerateStitchingOrder(motif knitting.Motif, fabricWidth int) (knitting.Fabric, error) {
+	if fabricWidth < 1 {
+		return nil, errors.New("fabricWidth must be a positive integer")
+	}
 
-	// The actual string that went past the end of the last row, it represents
-	// the start of this row.
-	overhangStr := knitting.Row(motif[len(motif)-overhang:])
+	fabric, _, err := phase.Generate(fabricWidth, []knitting.Motif{motif}, phase.Continue)
+	if err != nil {
+		return nil, err
+	}
 
-	// The overhang from the previous row was so long it fills the entire row
-	if overhang > fabricWidth {
-		return overhangStr[:fabricWidth], overhang - fabricWidth
+	return fabric, nil
+}
+
+// Generate the zigzag pattern as a chart of the front of the fabric.
+// The first row knit is at the bottom of the chart.
+func GenerateZigzagFabric(motif knitting.Motif, fabricWidth int) (knitting.Fabric, error) {
+	fabric, err := GenerateStitchingOrder(motif, fabricWidth)
+	if err != nil {
+		return nil, err
 	}
 
-	repeats := (fabricWidth - overhang) / len(motif)
-	remaining := (fabricWidth - overhang) % len(motif)
+	return fabric.HandleReverseRows().Rotate180(), nil
+}
 
-	nextOverhang := 0
-	if remaining != 0 {
-		nextOverhang = len(motif) - remaining
+func GenerateZigzagPattern(motif knitting.Motif, fabricWidth int) ([]string, error) {
+	fabric, err := GenerateZigzagFabric(motif, fabricWidth)
+	if err != nil {
+		return nil, err
 	}
 
-	left := overhangStr
-	middle := knitting.Row(motif.Repeat(uint(repeats)))
-	right := knitting.Row(motif[:remaining])
+	return fabric.ToStrings(), nil
+}
 
-	row := left
-	row = append(row, middle...)
-	row = append(row, right...)
+// Where a motif begins in the chart of a zigzag pattern made from several
+// motifs
+type MotifStart struct {
+	// Index of the motif in the list of motifs
+	Motif int
+	// Row of the chart, counting from 1 at the bottom
+	Row int
+	// Column of the chart, counting from 1 at the left
+	Column int
+}
+
+// Work the motifs one after another in a single stream, each one repeated
+// switchEvery times before switching to the next motif
+func combineMotifs(motifs []knitting.Motif, switchEvery int) (knitting.Motif, []int) {
+	combined := knitting.Motif{}
+	starts := make([]int, len(motifs))
+	for i, motif := range motifs {
+		starts[i] = len(combined)
+		combined = append(combined, motif.Repeat(uint(switchEvery))...)
+	}
 
-	return row, nextOverhang
+	return combined, starts
 }
 
-// Generate a pattern based on repeating the motif until it aligns
-// with the width of the fabric. This works even if the motif is longer
-// than a single row!
-func generateRawPattern(motif knitting.Motif, fabricWidth int) knitting.Fabric {
-	overhang := 0
-	fabric := knitting.Fabric{}
-	for {
-		var row knitting.Row
-		row, overhang = fillRow(overhang, motif, fabricWidth)
-		fabric = append(fabric, row)
-		if overhang == 0 {
-			break
+func checkSequence(motifs []knitting.Motif, switchEvery int) error {
+	if len(motifs) < 1 {
+		return errors.New("motifs must be non-empty")
+	}
+
+	if switchEvery < 1 {
+		return errors.New("switchEvery must be a positive integer")
+	}
+
+	total := 0
+	for i, motif := range motifs {
+		if len(motif) == 0 {
+			return fmt.Errorf("motif %d must not be empty", i+1)
 		}
+
+		// Compare without multiplying, which could overflow
+		if switchEvery > (MaxSequenceLength-total)/len(motif) {
+			return fmt.Errorf("the motifs repeated %d times each add up to more than %d stitches", switchEvery, MaxSequenceLength)
+		}
+		total += len(motif) * switchEvery
 	}
 
-	return fabric
+	return nil
 }
 
-// Possibly repeat the entire rows twice to ensure the pattern has
-// an even number of rows. This ensures that the pattern returns to the
-// starting place when knit.
+// Generate a zigzag pattern from several motifs, as the rows are worked by
+// the knitter. The motifs are worked one after another in a continuous
+// stream, so a row may end partway through one motif and the next row
+// continues from there. Each motif is repeated switchEvery times before
+// switching to the next one, and after the last motif the stream starts
+// over with the first.
 //
-// The input rows is treated as an immutable slice, a new slice is always
-// allocated.
-func ensureEvenRowCount(fabric knitting.Fabric) knitting.Fabric {
-	n := len(fabric)
-	if n%2 == 0 {
-		result := make(knitting.Fabric, n)
-		_ = copy(result, fabric)
-		return result
+// This also returns every place in one repeat of the chart where a motif
+// begins, ordered by row and then column.
+func GenerateSequence(motifs []knitting.Motif, switchEvery int, fabricWidth int) (knitting.Fabric, []MotifStart, error) {
+	if fabricWidth < 1 {
+		return nil, nil, errors.New("fabricWidth must be a positive integer")
 	}
 
-	result := make(knitting.Fabric, 0, 2*len(fabric))
-	result = append(result, fabric...)
-	result = append(result, fabric...)
-	return result
-}
+	if err := checkSequence(motifs, switchEvery); err != nil {
+		return nil, nil, err
+	}
 
-// pass in rows of the fabric listed as knit ('v') and purl ('-') as the
-// knitter
-//
-// However, every second row is knit on the wrong side of the fabric, so
-// the direction of stitching is reversed, and also knits show up as purls
-// and vice-versa on the front. This function takes care of this.
-//
-// Rows is treated as an immutable slice, so a new slice is allocated.
-func handleReverseRows(fabric knitting.Fabric) knitting.Fabric {
-	result := make(knitting.Fabric, len(fabric))
-	_ = copy(result, fabric)
-
-	for i, row := range fabric {
-		if i%2 == 0 {
-			result[i] = row
-		} else {
-			result[i] = row.Reverse().SwapKnitsAndPurls()
+	combined, offsets := combineMotifs(motifs, switchEvery)
+	fabric, phases, err := phase.Generate(fabricWidth, []knitting.Motif{combined}, phase.Continue)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	motifAt := map[int]int{}
+	for i, offset := range offsets {
+		motifAt[offset] = i
+	}
+
+	starts := []MotifStart{}
+	for row, rowPhase := range phases {
+		for i := 0; i < fabricWidth; i++ {
+			motif, ok := motifAt[rowPhase.Index(len(combined), i)]
+			if !ok {
+				continue
+			}
+
+			// Wrong side rows are worked from the left edge of the chart
+			column := fabricWidth - i
+			if row%2 == 1 {
+				column = i + 1
+			}
+			starts = append(starts, MotifStart{motif, row + 1, column})
 		}
 	}
 
-	return result
+	sort.Slice(starts, func(i, j int) bool {
+		if starts[i].Row != starts[j].Row {
+			return starts[i].Row < starts[j].Row
+		}
+		return starts[i].Column < starts[j].Column
+	})
+
+	return fabric, starts, nil
 }
 
-func GenerateZigzagPattern(motif knitting.Motif, fabricWidth int) ([]string, error) {
-	if fabricWidth < 1 {
-		return nil, errors.New("fabricWidth must be a positive integer")
+// Generate a shaped zigzag pattern where each row has its own width, as the
+// rows are worked by the knitter. The motifs are worked one after another
+// in a continuous stream like GenerateSequence, each one repeated
+// switchEvery times, so the motif carries over from row to row no matter
+// how wide the rows are.
+func GenerateShaped(widths []int, motifs []knitting.Motif, switchEvery int) (knitting.Fabric, []phase.Phase, error) {
+	if err := checkSequence(motifs, switchEvery); err != nil {
+		return nil, nil, err
 	}
 
-	fabric := generateRawPattern(motif, fabricWidth)
-	fabric = ensureEvenRowCount(fabric)
-	fabric = handleReverseRows(fabric)
-	fabric = fabric.Rotate180()
-
-	return fabric.ToStrings(), nil
+	combined, _ := combineMotifs(motifs, switchEvery)
+	return phase.GenerateShaped(widths, []knitting.Motif{combined}, phase.Continue)
 }
@@ -1,6 +1,7 @@
 package zigzag
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/ptrgags/mindless-stitchcraft/checks"
@@ -191,4 +192,120 @@ func TestGenerateZigzagPattern(t *testing.T) {
 			})
 		}
 	})
+	t.Run("motif that changes the row width returns error", func(t *testing.T) {
+		motif, _ := knitting.ParseMotif("vo")
+
+		rows, err := GenerateZigzagPattern(motif, 4)
+
+		checks.CheckHasError(t, rows, err, "row 1 would change the row width")
+	})
+
+	t.Run("balanced increases and decreases are allowed", func(t *testing.T) {
+		motif, _ := knitting.ParseMotif("o/v")
+
+		rows, err := GenerateZigzagPattern(motif, 6)
+
+		expectedRows := []string{
+			"oz-oz-",
+			"v/ov/o",
+		}
+		checks.CheckHasNoError(t, rows, err)
+		checks.CheckSlicesEqual(t, rows, expectedRows)
+	})
+}
+
+func TestGenerateSequence(t *testing.T) {
+	first, _ := knitting.ParseMotif("vv-")
+	second, _ := knitting.ParseMotif("v---")
+
+	t.Run("invalid switchEvery returns error", func(t *testing.T) {
+		fabric, _, err := GenerateSequence([]knitting.Motif{first}, 0, 5)
+
+		checks.CheckHasError(t, fabric, err, "switchEvery must be a positive integer")
+	})
+
+	t.Run("switchEvery that is too large returns error", func(t *testing.T) {
+		cases := []int{2000000000, 1429}
+
+		for _, switchEvery := range cases {
+			fabric, _, err := GenerateSequence([]knitting.Motif{first, second}, switchEvery, 5)
+
+			checks.CheckHasError(t, fabric, err, fmt.Sprintf("the motifs repeated %d times each add up to more than 10000 stitches", switchEvery))
+		}
+	})
+
+	t.Run("empty motif returns error", func(t *testing.T) {
+		fabric, _, err := GenerateSequence([]knitting.Motif{first, {}}, 1, 5)
+
+		checks.CheckHasError(t, fabric, err, "motif 2 must not be empty")
+	})
+
+	t.Run("single motif matches the zigzag pattern", func(t *testing.T) {
+		expected, _ := GenerateStitchingOrder(first, 5)
+
+		fabric, starts, err := GenerateSequence([]knitting.Motif{first}, 1, 5)
+
+		checks.CheckHasNoError(t, fabric, err)
+		checks.CheckSlicesEqual(t, fabric.ToStrings(), expected.ToStrings())
+		// 6 rows of 5 stitches fit 10 repeats of the motif
+		if len(starts) != 10 {
+			t.Errorf("expected the motif to begin 10 times, got %v", starts)
+		}
+	})
+
+	t.Run("motifs continue across rows", func(t *testing.T) {
+		fabric, _, err := GenerateSequence([]knitting.Motif{first, second}, 1, 5)
+
+		checks.CheckHasNoError(t, fabric, err)
+		// The stream is vv-v--- and each row picks up where the last one
+		// ended. It takes 7 rows to line up again, doubled to end on the
+		// wrong side.
+		checks.CheckSlicesEqual(t, fabric.ToStrings()[:3], []string{"vv-v-", "--vv-", "v---v"})
+		if len(fabric) != 14 {
+			t.Errorf("expected 14 rows, got %d", len(fabric))
+		}
+	})
+
+	t.Run("switchEvery repeats each motif", func(t *testing.T) {
+		fabric, _, err := GenerateSequence([]knitting.Motif{first, second}, 2, 10)
+
+		checks.CheckHasNoError(t, fabric, err)
+		checks.CheckSlicesEqual(t, fabric.ToStrings()[:1], []string{"vv-vv-v---"})
+	})
+
+	t.Run("lists where each motif begins in the chart", func(t *testing.T) {
+		_, starts, err := GenerateSequence([]knitting.Motif{first, second}, 1, 7)
+
+		checks.CheckHasNoError(t, starts, err)
+		expected := []MotifStart{{1, 1, 4}, {0, 1, 7}, {0, 2, 1}, {1, 2, 4}}
+		checks.CheckSlicesEqual(t, starts, expected)
+	})
+}
+
+func TestGenerateShaped(t *testing.T) {
+	t.Run("empty motif list returns error", func(t *testing.T) {
+		result, _, err := GenerateShaped([]int{3}, []knitting.Motif{}, 1)
+
+		checks.CheckHasError(t, result, err, "motifs must be non-empty")
+	})
+
+	t.Run("motifs continue across rows of different widths", func(t *testing.T) {
+		first, _ := knitting.ParseMotif("vv")
+		second, _ := knitting.ParseMotif("-")
+
+		result, _, err := GenerateShaped([]int{1, 3, 5}, []knitting.Motif{first, second}, 1)
+
+		checks.CheckHasNoError(t, result, err)
+		checks.CheckSlicesEqual(t, result.ToStrings(), []string{"v", "v-v", "v-vv-"})
+	})
+
+	t.Run("each motif is repeated before switching", func(t *testing.T) {
+		first, _ := knitting.ParseMotif("v")
+		second, _ := knitting.ParseMotif("-")
+
+		result, _, err := GenerateShaped([]int{1, 3, 5}, []knitting.Motif{first, second}, 2)
+
+		checks.CheckHasNoError(t, result, err)
+		checks.CheckSlicesEqual(t, result.ToStrings(), []string{"v", "v--", "vv--v"})
+	})
 }
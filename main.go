@@ -2,20 +2,832 @@ package main
 
 import (
 	"errors"
+	"flag"
 	"fmt"
+	"math"
 	"os"
+	"slices"
+	"sort"
 	"strconv"
+	"strings"
+	"text/tabwriter"
 
 	"github.com/ptrgags/mindless-stitchcraft/bracelets"
 	"github.com/ptrgags/mindless-stitchcraft/bracelets/repeat"
 	"github.com/ptrgags/mindless-stitchcraft/knitting"
+	"github.com/ptrgags/mindless-stitchcraft/knitting/automaton"
+	"github.com/ptrgags/mindless-stitchcraft/knitting/border"
+	"github.com/ptrgags/mindless-stitchcraft/knitting/cable"
+	"github.com/ptrgags/mindless-stitchcraft/knitting/colorwork"
+	"github.com/ptrgags/mindless-stitchcraft/knitting/curl"
+	"github.com/ptrgags/mindless-stitchcraft/knitting/doubleknit"
+	"github.com/ptrgags/mindless-stitchcraft/knitting/explore"
+	"github.com/ptrgags/mindless-stitchcraft/knitting/follow"
+	"github.com/ptrgags/mindless-stitchcraft/knitting/instructions"
+	"github.com/ptrgags/mindless-stitchcraft/knitting/lace"
+	"github.com/ptrgags/mindless-stitchcraft/knitting/mosaic"
+	"github.com/ptrgags/mindless-stitchcraft/knitting/phase"
+	"github.com/ptrgags/mindless-stitchcraft/knitting/plan"
+	"github.com/ptrgags/mindless-stitchcraft/knitting/round"
+	"github.com/ptrgags/mindless-stitchcraft/knitting/shape"
+	"github.com/ptrgags/mindless-stitchcraft/knitting/solve"
+	"github.com/ptrgags/mindless-stitchcraft/knitting/sources"
+	"github.com/ptrgags/mindless-stitchcraft/knitting/symmetry"
 	"github.com/ptrgags/mindless-stitchcraft/knitting/sync"
 	"github.com/ptrgags/mindless-stitchcraft/knitting/zigzag"
+	"github.com/ptrgags/mindless-stitchcraft/pooling"
 )
 
+// Parse a motif given on the command line. It may be written with stitch
+// symbols (e.g. "vv---"), abbreviations (e.g. "k2 p3"), or generated from a
+// source (e.g. "fibonacci:13").
+//
+// This also returns a note for the user if the motif is cut from a sequence
+// that never repeats, or an empty string otherwise.
+func parseMotif(motifStr string) (knitting.Motif, string, error) {
+	// Colons never appear in stitch symbols or abbreviations
+	if !strings.Contains(motifStr, ":") {
+		motif, err := knitting.ParseAnyMotif(motifStr)
+		return motif, "", err
+	}
+
+	motif, source, err := sources.Parse(motifStr)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if !source.Aperiodic {
+		return motif, "", nil
+	}
+
+	note := fmt.Sprintf("Note: %s never repeats, so the motif is only its first %d stitches and the pattern only repeats because they do", source.Name, len(motif))
+	return motif, note, nil
+}
+
+// Parse motifs given on the command line, see parseMotif. Each distinct
+// note is only returned once.
+func parseMotifs(motifStrs []string) ([]knitting.Motif, []string, error) {
+	motifs := make([]knitting.Motif, len(motifStrs))
+	notes := []string{}
+	for i, motifStr := range motifStrs {
+		motif, note, err := parseMotif(motifStr)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		motifs[i] = motif
+		if note != "" && !slices.Contains(notes, note) {
+			notes = append(notes, note)
+		}
+	}
+
+	return motifs, notes, nil
+}
+
+func printNotes(notes []string) {
+	for _, note := range notes {
+		if note != "" {
+			fmt.Println(note)
+		}
+	}
+}
+
+// Output options shared by the flat knitting commands
+type chartOptions struct {
+	side         string
+	instructions bool
+	noSymmetry   bool
+	border       string
+	rows         int
+	length       float64
+	rowGauge     float64
+}
+
+func addChartFlags(flags *flag.FlagSet) *chartOptions {
+	options := chartOptions{}
+	flags.StringVar(&options.side, "side", "front", "which side of the fabric to show: front, back or both")
+	flags.BoolVar(&options.instructions, "instructions", false, "print written row-by-row instructions instead of a chart")
+	flags.BoolVar(&options.noSymmetry, "no-symmetry", false, "do not print the symmetry group after the chart")
+	flags.StringVar(&options.border, "border", "none", "border around the fabric: none, garter:N, seed:N or slip")
+	flags.IntVar(&options.rows, "rows", 0, "preview the pattern repeated to this many rows")
+	flags.Float64Var(&options.length, "length", 0, "preview the pattern repeated to this length in cm, requires --row-gauge")
+	flags.Float64Var(&options.rowGauge, "row-gauge", 0, "rows per 10 cm, used with --length")
+	return &options
+}
+
+// How many rows to preview, or 0 to show a single repeat
+func (options *chartOptions) targetRows() (int, error) {
+	if options.rows < 0 {
+		return 0, errors.New("--rows must not be negative")
+	}
+
+	if options.length <= 0 {
+		return options.rows, nil
+	}
+
+	if options.rows > 0 {
+		return 0, errors.New("use either --rows or --length, not both")
+	}
+
+	if options.rowGauge <= 0 {
+		return 0, errors.New("--length requires a positive --row-gauge")
+	}
+
+	rows := int(math.Round(options.length * options.rowGauge / 10))
+	return max(rows, 1), nil
+}
+
+// Describe how a piece of the given number of rows lines up with the
+// pattern repeat
+func describeLength(rows int, repeat int) string {
+	repeats := rows / repeat
+	extra := rows % repeat
+	if extra == 0 {
+		return fmt.Sprintf("%d rows is exactly %d repeat(s) of %d rows.", rows, repeats, repeat)
+	}
+
+	description := fmt.Sprintf("%d rows cuts off the last repeat after %d of %d rows.", rows, extra, repeat)
+	if repeats == 0 {
+		return description + fmt.Sprintf(" The nearest clean ending is after %d rows.", repeat)
+	}
+
+	return description + fmt.Sprintf(" The nearest clean endings are after %d or %d rows.", repeats*repeat, (repeats+1)*repeat)
+}
+
+// Mark the lines of a preview chart where a full repeat of the pattern
+// ends, and where the piece is cut off mid-repeat. The chart lists the
+// last row first.
+func markRepeatBoundaries(lines []string, firstBodyLine int, body knitting.Fabric, repeat knitting.Fabric) {
+	if len(body)%len(repeat) != 0 {
+		lines[firstBodyLine] += fmt.Sprintf("  row %d: cut off mid-repeat", len(body))
+	}
+
+	for _, boundary := range repeat.RepeatBoundaries(len(body)) {
+		line := firstBodyLine + len(body) - boundary
+		lines[line] += fmt.Sprintf("  row %d: end of repeat %d", boundary, boundary/len(repeat))
+	}
+}
+
+// Print a flat fabric listed in stitching order, either as a chart or as
+// written instructions depending on the options. The notes from parsing the
+// motifs are printed right after the chart or instructions.
+func printFlatFabric(stitchingOrder knitting.Fabric, notes []string, options *chartOptions) error {
+	frame, err := border.ParseBorder(options.border)
+	if err != nil {
+		return err
+	}
+
+	targetRows, err := options.targetRows()
+	if err != nil {
+		return err
+	}
+
+	if options.instructions {
+		framed := frame.Apply(stitchingOrder)
+		for _, line := range instructions.FormatFlatWithBands(framed, frame.Rows) {
+			fmt.Println(line)
+		}
+		printNotes(notes)
+
+		if targetRows > 0 {
+			fmt.Println(describeLength(targetRows, len(stitchingOrder)))
+		}
+		return nil
+	}
+
+	body := stitchingOrder
+	if targetRows > 0 {
+		body = stitchingOrder.RepeatRows(targetRows)
+	}
+
+	framedChart := frame.Apply(body).HandleReverseRows().Rotate180()
+	front := frame.MarkChart(framedChart.ToStrings())
+	back := frame.MarkChart(framedChart.ReverseFace().ToStrings())
+
+	var rows []string
+	firstBodyLine := 0
+	if frame.Rows > 0 {
+		// Skip the top band and the line that marks it
+		firstBodyLine = frame.Rows + 1
+	}
+
+	switch options.side {
+	case "front":
+		rows = front
+	case "back":
+		rows = back
+	case "both":
+		width := 0
+		if len(front) > 0 {
+			width = len(front[0])
+		}
+
+		rows = make([]string, len(front)+1)
+		rows[0] = fmt.Sprintf("%-*s    %s", width, "Front", "Back")
+		for i := range front {
+			rows[i+1] = fmt.Sprintf("%-*s    %s", width, front[i], back[i])
+		}
+		firstBodyLine++
+	default:
+		return fmt.Errorf("side must be front, back or both, got %s", options.side)
+	}
+
+	if targetRows > 0 {
+		markRepeatBoundaries(rows, firstBodyLine, body, stitchingOrder)
+	}
+
+	for _, row := range rows {
+		fmt.Println(row)
+	}
+	printNotes(notes)
+
+	if targetRows > 0 {
+		fmt.Println(describeLength(targetRows, len(stitchingOrder)))
+	}
+
+	if !options.noSymmetry {
+		// Only the motif area repeats, so leave the border out of the
+		// symmetry
+		chart := stitchingOrder.HandleReverseRows().Rotate180()
+		report, err := symmetry.Classify(chart)
+		if err != nil {
+			return err
+		}
+		fmt.Println(report)
+	}
+
+	// Curling depends on the edges of the whole piece, border included
+	analysis, err := curl.Analyze(framedChart)
+	if err != nil {
+		return err
+	}
+	for _, warning := range analysis.Warnings() {
+		fmt.Println(warning)
+	}
+
+	return nil
+}
+
 func knitZigzag(args []string) error {
+	const usage = "usage: main.go knit-zigzag [--switch-every N] [--side SIDE] [--instructions] [--no-symmetry] [--border BORDER] [--rows N | --length CM --row-gauge ROWS] FABRIC_WIDTH MOTIF [MOTIF, ...]"
+
+	flags := flag.NewFlagSet("knit-zigzag", flag.ContinueOnError)
+	switchEvery := flags.Int("switch-every", 1, "how many times to repeat each motif before switching to the next one")
+	options := addChartFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return errors.New(usage)
+	}
+
+	args = flags.Args()
+	if len(args) < 2 {
+		return errors.New(usage)
+	}
+
+	fabricWidth, err := strconv.Atoi(args[0])
+	if err != nil {
+		return err
+	}
+
+	motifs, notes, err := parseMotifs(args[1:])
+	if err != nil {
+		return err
+	}
+
+	fabric, starts, err := zigzag.GenerateSequence(motifs, *switchEvery, fabricWidth)
+	if err != nil {
+		return err
+	}
+
+	if err := printFlatFabric(fabric, notes, options); err != nil {
+		return err
+	}
+
+	// Written instructions don't have columns to point to
+	if len(motifs) > 1 && !options.instructions {
+		printMotifStarts(motifs, starts, fabricWidth, options.side)
+	}
+
+	return nil
+}
+
+// List where each motif begins, counting rows and columns within the
+// motif area of the chart. Columns are counted on the back chart when only
+// the back is shown, and on the front chart otherwise.
+func printMotifStarts(motifs []knitting.Motif, starts []zigzag.MotifStart, fabricWidth int, side string) {
+	if side == "back" {
+		// The back is the front mirrored from left to right
+		mirrored := make([]zigzag.MotifStart, len(starts))
+		for i, start := range starts {
+			start.Column = fabricWidth + 1 - start.Column
+			mirrored[i] = start
+		}
+
+		sort.Slice(mirrored, func(i, j int) bool {
+			if mirrored[i].Row != mirrored[j].Row {
+				return mirrored[i].Row < mirrored[j].Row
+			}
+			return mirrored[i].Column < mirrored[j].Column
+		})
+		starts = mirrored
+	}
+
+	positions := make([][]string, len(motifs))
+	for _, start := range starts {
+		positions[start.Motif] = append(positions[start.Motif], fmt.Sprintf("row %d column %d", start.Row, start.Column))
+	}
+
+	for i, motif := range motifs {
+		fmt.Printf("Motif %d (%s) begins at: %s\n", i+1, knitting.Row(motif).ToString(), strings.Join(positions[i], ", "))
+	}
+}
+
+func describeSlope(slope int) string {
+	switch {
+	case slope == 0:
+		return "none, the motif stacks vertically"
+	case slope > 0:
+		return fmt.Sprintf("%d stitch(es) to the right every 2 rows", slope)
+	default:
+		return fmt.Sprintf("%d stitch(es) to the left every 2 rows", -slope)
+	}
+}
+
+func knitAnalyze(args []string) error {
+	if len(args) < 2 {
+		return errors.New("usage: main.go knit-analyze FABRIC_WIDTH MOTIF")
+	}
+
+	fabricWidth, err := strconv.Atoi(args[0])
+	if err != nil {
+		return err
+	}
+
+	motif, note, err := parseMotif(args[1])
+	if err != nil {
+		return err
+	}
+
+	analysis, err := zigzag.AnalyzePattern(len(motif), fabricWidth)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Vertical repeat: %d rows\n", analysis.RowRepeat)
+	fmt.Printf("Horizontal repeat: %d stitches\n", analysis.StitchRepeat)
+	fmt.Printf("Diagonal slope: %s\n", describeSlope(analysis.Slope))
+	fmt.Printf("Motifs per repeat: %d\n", analysis.MotifsPerRepeat)
+	printNotes([]string{note})
+
+	return nil
+}
+
+func knitSync(args []string) error {
+	const usage = "usage: main.go knit-sync [--side SIDE] [--instructions] [--no-symmetry] [--border BORDER] [--rows N | --length CM --row-gauge ROWS] FABRIC_WIDTH MOTIF [MOTIF, ...]"
+
+	flags := flag.NewFlagSet("knit-sync", flag.ContinueOnError)
+	options := addChartFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return errors.New(usage)
+	}
+
+	args = flags.Args()
+	if len(args) < 2 {
+		return errors.New(usage)
+	}
+
+	fabricWidth, err := strconv.Atoi(args[0])
+	if err != nil {
+		return err
+	}
+
+	motifStrs := args[1:]
+	motifs, notes, err := parseMotifs(motifStrs)
+	if err != nil {
+		return err
+	}
+
+	fabric, err := sync.GenerateStitchingOrder(uint(fabricWidth), motifs)
+
+	if err != nil {
+		return err
+	}
+
+	return printFlatFabric(fabric, notes, options)
+}
+
+func knitPhase(args []string) error {
+	const usage = "usage: main.go knit-phase [--rule RULE] [--side SIDE] [--instructions] [--no-symmetry] [--border BORDER] [--rows N | --length CM --row-gauge ROWS] FABRIC_WIDTH MOTIF [MOTIF, ...]"
+
+	flags := flag.NewFlagSet("knit-phase", flag.ContinueOnError)
+	ruleName := flags.String("rule", "continue", "where each row starts: continue, restart, reverse, shift:K or restart-every:N")
+	options := addChartFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return errors.New(usage)
+	}
+
+	args = flags.Args()
+	if len(args) < 2 {
+		return errors.New(usage)
+	}
+
+	rule, err := phase.ParseRule(*ruleName)
+	if err != nil {
+		return err
+	}
+
+	fabricWidth, err := strconv.Atoi(args[0])
+	if err != nil {
+		return err
+	}
+
+	motifs, notes, err := parseMotifs(args[1:])
+	if err != nil {
+		return err
+	}
+
+	fabric, _, err := phase.Generate(fabricWidth, motifs, rule)
+	if err != nil {
+		return err
+	}
+
+	return printFlatFabric(fabric, notes, options)
+}
+
+func knitFollow(args []string) error {
+	const usage = "usage: main.go knit-follow [--pattern zigzag|sync] [--state FILE] FABRIC_WIDTH MOTIF [MOTIF, ...]"
+
+	flags := flag.NewFlagSet("knit-follow", flag.ContinueOnError)
+	patternName := flags.String("pattern", "zigzag", "which pattern to follow: zigzag or sync")
+	statePath := flags.String("state", ".knit-follow.json", "file where the current row is saved")
+	if err := flags.Parse(args); err != nil {
+		return errors.New(usage)
+	}
+
+	args = flags.Args()
+	if len(args) < 2 {
+		return errors.New(usage)
+	}
+
+	fabricWidth, err := strconv.Atoi(args[0])
+	if err != nil {
+		return err
+	}
+
+	motifs, notes, err := parseMotifs(args[1:])
+	if err != nil {
+		return err
+	}
+
+	var fabric knitting.Fabric
+	switch *patternName {
+	case "zigzag":
+		fabric, _, err = zigzag.GenerateSequence(motifs, 1, fabricWidth)
+	case "sync":
+		fabric, err = sync.GenerateStitchingOrder(uint(fabricWidth), motifs)
+	default:
+		return fmt.Errorf("pattern must be zigzag or sync, got %s", *patternName)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	state := follow.State{
+		Pattern:     *patternName,
+		FabricWidth: fabricWidth,
+		Motifs:      make([]string, len(motifs)),
+		Row:         1,
+	}
+	for i, motif := range motifs {
+		state.Motifs[i] = knitting.Row(motif).ToString()
+	}
+
+	saved, err := follow.LoadState(*statePath)
+	switch {
+	case err == nil && saved.SamePattern(state):
+		state.Row = saved.Row
+		fmt.Printf("Resuming at row %d from %s\n", state.Row, *statePath)
+	case err == nil:
+		fmt.Printf("%s is for a different pattern, starting from row 1\n", *statePath)
+	case !errors.Is(err, os.ErrNotExist):
+		return err
+	}
+
+	follower, err := follow.NewFollower(fabric, state.Row)
+	if err != nil {
+		return err
+	}
+
+	err = follow.Run(os.Stdin, os.Stdout, follower, func(row int) error {
+		state.Row = row
+		return follow.SaveState(*statePath, state)
+	})
+	if err != nil {
+		return err
+	}
+
+	// Leave the notes until the end so they don't get in the way of the
+	// rows
+	printNotes(notes)
+	return nil
+}
+
+func knitExplore(args []string) error {
+	const usage = "usage: main.go knit-explore [--top N] FABRIC_WIDTH MAX_LENGTH"
+
+	flags := flag.NewFlagSet("knit-explore", flag.ContinueOnError)
+	top := flags.Int("top", 20, "how many of the best motifs to list, or 0 to list all of them")
+	if err := flags.Parse(args); err != nil {
+		return errors.New(usage)
+	}
+
+	args = flags.Args()
+	if len(args) < 2 {
+		return errors.New(usage)
+	}
+
+	fabricWidth, err := strconv.Atoi(args[0])
+	if err != nil {
+		return err
+	}
+
+	maxLength, err := strconv.Atoi(args[1])
+	if err != nil {
+		return err
+	}
+
+	results, err := explore.Explore(fabricWidth, maxLength)
+	if err != nil {
+		return err
+	}
+
+	if *top > 0 && *top < len(results) {
+		results = results[:*top]
+	}
+
+	table := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "Motif\tZigzag rows\tSync rows\tKnits")
+	for _, result := range results {
+		motif := knitting.Row(result.Motif).ToString()
+		fmt.Fprintf(table, "%s\t%d\t%d\t%d/%d\n", motif, result.ZigzagRows, result.SyncRows, result.Knits, len(result.Motif))
+	}
+
+	return table.Flush()
+}
+
+func knitSolve(args []string) error {
+	const usage = "usage: main.go knit-solve [--pattern all|zigzag|sync|round] [--max-length N] [--max-width N] ROW [ROW, ...]"
+
+	flags := flag.NewFlagSet("knit-solve", flag.ContinueOnError)
+	patternName := flags.String("pattern", "all", "which patterns to search: all, zigzag, sync or round")
+	maxLength := flags.Int("max-length", 8, "longest motif to try")
+	maxWidth := flags.Int("max-width", 0, "widest fabric to try, defaults to twice the chart width")
+	if err := flags.Parse(args); err != nil {
+		return errors.New(usage)
+	}
+
+	chart := flags.Args()
+	if len(chart) < 1 {
+		return errors.New(usage)
+	}
+
+	patterns, err := solve.ParsePatterns(*patternName)
+	if err != nil {
+		return err
+	}
+
+	if *maxWidth == 0 {
+		*maxWidth = 2 * len(chart[0])
+	}
+
+	solutions, err := solve.Solve(chart, patterns, *maxLength, *maxWidth)
+	if err != nil {
+		return err
+	}
+
+	if len(solutions) == 0 {
+		fmt.Printf("No solution with motifs up to %d stitches and widths up to %d\n", *maxLength, *maxWidth)
+		return nil
+	}
+
+	table := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "Pattern\tMotif\tWidth\tRows")
+	for _, solution := range solutions {
+		motif := knitting.Row(solution.Motif).ToString()
+		fmt.Fprintf(table, "%s\t%s\t%d\t%d\n", solution.Pattern, motif, solution.Width, solution.Rows)
+	}
+
+	return table.Flush()
+}
+
+func knitPlan(args []string) error {
+	const usage = "usage: main.go knit-plan [--pattern zigzag|sync] [--spread N] [--top N] MOTIF STITCHES_PER_10CM ROWS_PER_10CM WIDTH_CM LENGTH_CM"
+
+	flags := flag.NewFlagSet("knit-plan", flag.ContinueOnError)
+	patternName := flags.String("pattern", "zigzag", "which pattern to plan: zigzag or sync")
+	spread := flags.Int("spread", 10, "how many stitches narrower or wider than the target width to consider")
+	top := flags.Int("top", 10, "how many of the best widths to list, or 0 to list all of them")
+	if err := flags.Parse(args); err != nil {
+		return errors.New(usage)
+	}
+
+	args = flags.Args()
+	if len(args) < 5 {
+		return errors.New(usage)
+	}
+
+	pattern, err := plan.ParsePattern(*patternName)
+	if err != nil {
+		return err
+	}
+
+	motif, note, err := parseMotif(args[0])
+	if err != nil {
+		return err
+	}
+
+	numbers := make([]float64, 4)
+	for i, arg := range args[1:5] {
+		numbers[i], err = strconv.ParseFloat(arg, 64)
+		if err != nil {
+			return err
+		}
+	}
+
+	gauge := plan.Gauge{Stitches: numbers[0], Rows: numbers[1]}
+	target := plan.Target{Width: numbers[2], Length: numbers[3]}
+	candidates, err := plan.Plan(pattern, motif, gauge, target, *spread)
+	if err != nil {
+		return err
+	}
+
+	if *top > 0 && *top < len(candidates) {
+		candidates = candidates[:*top]
+	}
+
+	table := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "Cast on\tWidth\tRepeat\tFull repeats\tSymmetry\tYarn")
+	for _, candidate := range candidates {
+		symmetryGroup := candidate.Symmetry
+		if !candidate.Interesting {
+			symmetryGroup += " (stripes)"
+		}
+
+		fmt.Fprintf(
+			table,
+			"%d sts\t%.1f cm\t%d rows (%.1f cm)\t%d\t%s\t~%.0f m\n",
+			candidate.Stitches,
+			candidate.Width,
+			candidate.RepeatRows,
+			candidate.RepeatHeight,
+			candidate.FullRepeats,
+			symmetryGroup,
+			candidate.YarnMeters,
+		)
+	}
+
+	if err := table.Flush(); err != nil {
+		return err
+	}
+
+	printNotes([]string{note})
+	return nil
+}
+
+func printRoundStarts(starts []round.RoundStart) {
+	fmt.Println("Round starts:")
+	for i, start := range starts {
+		line := fmt.Sprintf("round %d: motif %d, stitch %d", i+1, start.Motif+1, start.MotifIndex+1)
+		if start.Drift != 0 {
+			line += fmt.Sprintf(", jog of %d stitches", start.Drift)
+		}
+		if start.Cut != 0 {
+			line += fmt.Sprintf(", previous round cut %d stitches short", start.Cut)
+		}
+		fmt.Println(line)
+	}
+}
+
+func knitRound(args []string) error {
+	const usage = "usage: main.go knit-round [--restart] CIRCUMFERENCE MOTIF [MOTIF, ...]"
+
+	flags := flag.NewFlagSet("knit-round", flag.ContinueOnError)
+	restart := flags.Bool("restart", false, "restart the motif at the beginning of each round")
+	if err := flags.Parse(args); err != nil {
+		return errors.New(usage)
+	}
+
+	args = flags.Args()
+	if len(args) < 2 {
+		return errors.New(usage)
+	}
+
+	circumference, err := strconv.Atoi(args[0])
+	if err != nil {
+		return err
+	}
+
+	motifStrs := args[1:]
+	if !*restart && len(motifStrs) > 1 {
+		return errors.New("multiple motifs are only supported with --restart")
+	}
+
+	motifs, notes, err := parseMotifs(motifStrs)
+	if err != nil {
+		return err
+	}
+
+	var rows []string
+	var starts []round.RoundStart
+	if *restart {
+		rows, starts, err = round.GenerateRestartPattern(uint(circumference), motifs)
+	} else {
+		rows, starts, err = round.GenerateSpiralPattern(motifs[0], circumference)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		fmt.Println(row)
+	}
+	printNotes(notes)
+	printRoundStarts(starts)
+
+	return nil
+}
+
+func knitShape(args []string) error {
+	const usage = "usage: main.go knit-shape [--pattern zigzag|sync] [--switch-every N] SCHEDULE MOTIF [MOTIF, ...]"
+
+	flags := flag.NewFlagSet("knit-shape", flag.ContinueOnError)
+	patternName := flags.String("pattern", "zigzag", "which pattern to shape: zigzag or sync")
+	switchEvery := flags.Int("switch-every", 1, "for zigzag, how many times to repeat each motif before switching to the next one")
+	if err := flags.Parse(args); err != nil {
+		return errors.New(usage)
+	}
+
+	args = flags.Args()
+	if len(args) < 2 {
+		return errors.New(usage)
+	}
+
+	widths, err := shape.ParseSchedule(args[0])
+	if err != nil {
+		return err
+	}
+
+	motifs, notes, err := parseMotifs(args[1:])
+	if err != nil {
+		return err
+	}
+
+	var fabric knitting.Fabric
+	var phases []phase.Phase
+	switch *patternName {
+	case "zigzag":
+		fabric, phases, err = zigzag.GenerateShaped(widths, motifs, *switchEvery)
+	case "sync":
+		fabric, phases, err = sync.GenerateShaped(widths, motifs)
+	default:
+		return fmt.Errorf("pattern must be zigzag or sync, got %s", *patternName)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	// List the width of each row next to the chart, which starts at the
+	// last row
+	chart := shape.Chart(fabric)
+	for i, row := range chart {
+		fmt.Printf("%s  %d\n", row, widths[len(widths)-1-i])
+	}
+	printNotes(notes)
+
+	repeat, found := shape.FindRepeat(widths, phases)
+	if !found {
+		fmt.Printf("The stitch pattern does not repeat within %d rows\n", len(widths))
+		return nil
+	}
+
+	fmt.Printf("The stitch pattern repeats every %d rows, and the piece grows by %+d stitch(es) per repeat\n", repeat.Rows, repeat.Growth)
+	return nil
+}
+
+func knitAutomaton(args []string) error {
+	const usage = "usage: main.go knit-automaton [--edges wrap|fixed] FABRIC_WIDTH RULE [SEED]"
+
+	flags := flag.NewFlagSet("knit-automaton", flag.ContinueOnError)
+	edgesName := flags.String("edges", "wrap", "what is past the edges of the fabric: wrap or fixed")
+	if err := flags.Parse(args); err != nil {
+		return errors.New(usage)
+	}
+
+	args = flags.Args()
 	if len(args) < 2 {
-		return errors.New("usage: main.go knit-zigzag FABRIC_WIDTH MOTIF")
+		return errors.New(usage)
 	}
 
 	fabricWidth, err := strconv.Atoi(args[0])
@@ -23,26 +835,136 @@ func knitZigzag(args []string) error {
 		return err
 	}
 
-	motif, err := knitting.ParseMotif(args[1])
+	if fabricWidth < 1 {
+		return errors.New("fabricWidth must be a positive integer")
+	}
+
+	rule, err := automaton.ParseRule(args[1])
 	if err != nil {
 		return err
 	}
 
-	rows, err := zigzag.GenerateZigzagPattern(motif, fabricWidth)
+	edges, err := automaton.ParseEdges(*edgesName)
 	if err != nil {
 		return err
 	}
 
-	for _, row := range rows {
+	// By default, start with a single purl in the middle
+	seed := knitting.Row(knitting.Motif{knitting.Knit}.RepeatToLength(uint(fabricWidth)))
+	seed[fabricWidth/2] = knitting.Purl
+	note := ""
+	if len(args) > 2 {
+		var motif knitting.Motif
+		motif, note, err = parseMotif(args[2])
+		if err != nil {
+			return err
+		}
+		seed = knitting.Row(motif.RepeatToLength(uint(fabricWidth)))
+	}
+
+	evolution, err := automaton.Evolve(seed, rule, edges)
+	if err != nil {
+		return err
+	}
+
+	for _, row := range evolution.Rows.HandleReverseRows().Rotate180().ToStrings() {
 		fmt.Println(row)
 	}
+	printNotes([]string{note})
+
+	if evolution.SetupRows > 0 {
+		fmt.Printf("The first %d row(s) are set up rows, the repeat starts at row %d\n", evolution.SetupRows, evolution.SetupRows+1)
+	}
+
+	first := evolution.SetupRows + 1
+	last := evolution.SetupRows + evolution.RepeatRows
+	if evolution.RepeatRows != evolution.Cycle {
+		fmt.Printf("Repeat rows %d-%d. The automaton cycles every %d row(s), doubled so the repeat starts on the same side\n", first, last, evolution.Cycle)
+	} else {
+		fmt.Printf("Repeat rows %d-%d. The automaton cycles every %d row(s)\n", first, last, evolution.Cycle)
+	}
 
 	return nil
 }
 
-func knitSync(args []string) error {
+func knitCable(args []string) error {
+	const usage = "usage: main.go knit-cable [--repeats N] PANEL_WIDTH ROW [ROW, ...]"
+
+	flags := flag.NewFlagSet("knit-cable", flag.ContinueOnError)
+	repeats := flags.Int("repeats", 0, "how many repeats of the rows to chart, defaults to until every stitch returns to its column")
+	if err := flags.Parse(args); err != nil {
+		return errors.New(usage)
+	}
+
+	args = flags.Args()
+	if len(args) < 2 {
+		return errors.New(usage)
+	}
+
+	width, err := strconv.Atoi(args[0])
+	if err != nil {
+		return err
+	}
+
+	rows := make([][]cable.Cross, len(args[1:]))
+	for i, rowStr := range args[1:] {
+		row, err := cable.ParseRow(rowStr)
+		if err != nil {
+			return fmt.Errorf("row %d: %w", i+1, err)
+		}
+		rows[i] = row
+	}
+
+	pattern, err := cable.New(width, rows)
+	if err != nil {
+		return err
+	}
+
+	aligned := pattern.RepeatsUntilAligned()
+	chartRepeats := *repeats
+	if chartRepeats < 1 {
+		chartRepeats = aligned
+	}
+
+	paths := pattern.Paths(chartRepeats)
+	for i, line := range paths {
+		rowIndex := len(paths) - 1 - i
+		crossings := make([]string, len(rows[rowIndex%len(rows)]))
+		for j, cross := range rows[rowIndex%len(rows)] {
+			crossings[j] = cross.String()
+		}
+		fmt.Println(strings.TrimSpace(fmt.Sprintf("%s  %d %s", line, rowIndex+1, strings.Join(crossings, " "))))
+	}
+
+	fmt.Printf("Every stitch returns to its column after %d repeat(s) of %d rows\n", aligned, len(rows))
+	for _, cycle := range pattern.Repeat().CycleDecomposition() {
+		if len(cycle) < 2 {
+			continue
+		}
+
+		columns := make([]string, len(cycle))
+		for i, column := range cycle {
+			columns[i] = strconv.Itoa(int(column) + 1)
+		}
+		fmt.Printf("Each repeat, stitches travel through columns %s\n", strings.Join(columns, " -> "))
+	}
+
+	return nil
+}
+
+func knitLace(args []string) error {
+	const usage = "usage: main.go knit-lace [--pattern zigzag|sync] [--instructions] FABRIC_WIDTH MOTIF [MOTIF, ...]"
+
+	flags := flag.NewFlagSet("knit-lace", flag.ContinueOnError)
+	patternName := flags.String("pattern", "zigzag", "how to repeat the motifs: zigzag or sync")
+	showInstructions := flags.Bool("instructions", false, "print written instructions for each row")
+	if err := flags.Parse(args); err != nil {
+		return errors.New(usage)
+	}
+
+	args = flags.Args()
 	if len(args) < 2 {
-		return errors.New("usage: main.go knit-sync FABRIC_WIDTH MOTIF [MOTIF, ...]")
+		return errors.New(usage)
 	}
 
 	fabricWidth, err := strconv.Atoi(args[0])
@@ -50,28 +972,331 @@ func knitSync(args []string) error {
 		return err
 	}
 
-	motifStrs := args[1:]
-	motifs := make([]knitting.Motif, len(motifStrs))
-	for i, motifStr := range motifStrs {
-		motif, err := knitting.ParseMotif(motifStr)
+	motifs, notes, err := parseMotifs(args[1:])
+	if err != nil {
+		return err
+	}
+
+	var pattern lace.Pattern
+	switch *patternName {
+	case "zigzag":
+		if len(motifs) > 1 {
+			return errors.New("multiple motifs are only supported with --pattern sync")
+		}
+		pattern, err = lace.GenerateZigzag(fabricWidth, motifs[0])
+	case "sync":
+		pattern, err = lace.GenerateSync(fabricWidth, motifs)
+	default:
+		return fmt.Errorf("pattern must be zigzag or sync, got %s", *patternName)
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, line := range pattern.Chart() {
+		fmt.Println(line)
+	}
+	printNotes(notes)
+
+	fmt.Printf("Stitch count change per row: %s\n", pattern.DescribeChanges())
+	if pattern.RepeatStart > 0 {
+		fmt.Printf("The first %d row(s) are set up rows, the repeat starts at row %d\n", pattern.RepeatStart, pattern.RepeatStart+1)
+	}
+
+	if *showInstructions {
+		fmt.Println()
+		for _, line := range pattern.Instructions() {
+			fmt.Println(line)
+		}
+	}
+
+	return nil
+}
+
+func knitDouble(args []string) error {
+	const usage = "usage: main.go knit-double [--pattern zigzag|sync|rows] [--colors AB] [--ansi] [--instructions] FABRIC_WIDTH MOTIF [MOTIF, ...]"
+
+	flags := flag.NewFlagSet("knit-double", flag.ContinueOnError)
+	patternName := flags.String("pattern", "zigzag", "how to make the chart: zigzag, sync, or rows to use each motif as one chart row")
+	colorNames := flags.String("colors", "AB", "the two colors of yarn, as one letter each")
+	ansi := flags.Bool("ansi", false, "shade the faces with terminal colors")
+	showInstructions := flags.Bool("instructions", false, "print written instructions for each row")
+	if err := flags.Parse(args); err != nil {
+		return errors.New(usage)
+	}
+
+	args = flags.Args()
+	if len(args) < 2 {
+		return errors.New(usage)
+	}
+
+	colorRunes := []rune(*colorNames)
+	if len(colorRunes) != 2 {
+		return fmt.Errorf("colors must be two letters, got %s", *colorNames)
+	}
+
+	fabricWidth, err := strconv.Atoi(args[0])
+	if err != nil {
+		return err
+	}
+
+	if fabricWidth < 1 {
+		return errors.New("fabricWidth must be a positive integer")
+	}
+
+	motifs, notes, err := parseMotifs(args[1:])
+	if err != nil {
+		return err
+	}
+
+	var chart knitting.Fabric
+	switch *patternName {
+	case "zigzag":
+		if len(motifs) > 1 {
+			return errors.New("multiple motifs are only supported with --pattern sync or rows")
+		}
+		chart, err = zigzag.GenerateZigzagFabric(motifs[0], fabricWidth)
+	case "sync":
+		chart, err = sync.GenerateFabric(uint(fabricWidth), motifs)
+	case "rows":
+		chart = doubleknit.ChartFromMotifs(fabricWidth, motifs)
+	default:
+		return fmt.Errorf("pattern must be zigzag, sync or rows, got %s", *patternName)
+	}
+	if err != nil {
+		return err
+	}
+
+	pattern, err := doubleknit.New(chart, [2]rune{colorRunes[0], colorRunes[1]})
+	if err != nil {
+		return err
+	}
+
+	front := knitting.RenderColors(pattern.FrontColors(), colorRunes, *ansi)
+	back := knitting.RenderColors(pattern.BackColors(), colorRunes, *ansi)
+	fmt.Printf("%-*s  Back\n", fabricWidth, "Front")
+	for i := range front {
+		fmt.Printf("%s  %s\n", front[i], back[i])
+	}
+	printNotes(notes)
+
+	if *showInstructions {
+		fmt.Println()
+		for _, line := range pattern.Instructions() {
+			fmt.Println(line)
+		}
+	}
+
+	return nil
+}
+
+func knitColorwork(args []string) error {
+	const usage = "usage: main.go knit-colorwork [--pattern zigzag|sync|round] [--max-float N] [--ansi] WIDTH MOTIF [MOTIF...]"
+
+	flags := flag.NewFlagSet("knit-colorwork", flag.ContinueOnError)
+	pattern := flags.String("pattern", "zigzag", "how to repeat the motifs: zigzag, sync or round")
+	maxFloat := flags.Int("max-float", 5, "longest float allowed on the wrong side, in stitches")
+	ansi := flags.Bool("ansi", false, "shade the chart with terminal colors")
+	if err := flags.Parse(args); err != nil {
+		return errors.New(usage)
+	}
+
+	args = flags.Args()
+	if len(args) < 2 {
+		return errors.New(usage)
+	}
+
+	width, err := strconv.Atoi(args[0])
+	if err != nil {
+		return err
+	}
+
+	if *maxFloat < 1 {
+		return errors.New("max-float must be a positive integer")
+	}
+
+	motifs := make([]colorwork.Motif, len(args[1:]))
+	for i, motifStr := range args[1:] {
+		motif, err := colorwork.ParseMotif(motifStr)
 		if err != nil {
 			return err
 		}
-
 		motifs[i] = motif
 	}
 
-	rows, err := sync.GeneratePattern(uint(fabricWidth), motifs)
+	var fabric colorwork.Fabric
+	switch *pattern {
+	case "zigzag":
+		fabric, err = colorwork.GenerateZigzag(width, motifs)
+	case "sync":
+		fabric, err = colorwork.GenerateSync(width, motifs)
+	case "round":
+		if len(motifs) != 1 {
+			return errors.New("round colorwork takes a single motif")
+		}
+		fabric, err = colorwork.GenerateRound(width, motifs[0])
+	default:
+		return fmt.Errorf("pattern must be zigzag, sync or round, got %s", *pattern)
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, line := range knitting.RenderColors(fabric.Chart(), colorwork.Colors(motifs), *ansi) {
+		fmt.Println(line)
+	}
+
+	floats := fabric.Floats(*maxFloat)
+	if len(floats) == 0 {
+		fmt.Printf("No floats longer than %d stitches\n", *maxFloat)
+		return nil
+	}
+
+	fmt.Printf("Floats longer than %d stitches (catch these on the wrong side):\n", *maxFloat)
+	table := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "Row\tColumn\tColor\tLength")
+	for _, float := range floats {
+		fmt.Fprintf(table, "%d\t%d\t%c\t%d\n", float.Row, float.Start, float.Color, float.Length)
+	}
+
+	return table.Flush()
+}
+
+func knitMosaic(args []string) error {
+	const usage = "usage: main.go knit-mosaic [--rule RULE] [--colors AB] [--ansi] FABRIC_WIDTH MOTIF [MOTIF, ...]"
+
+	flags := flag.NewFlagSet("knit-mosaic", flag.ContinueOnError)
+	ruleName := flags.String("rule", "continue", "where each row pair starts: continue, restart, reverse, shift:K or restart-every:N")
+	colorNames := flags.String("colors", "DL", "the two colors of yarn, as one letter each")
+	ansi := flags.Bool("ansi", false, "shade the front of the fabric with terminal colors")
+	if err := flags.Parse(args); err != nil {
+		return errors.New(usage)
+	}
 
+	args = flags.Args()
+	if len(args) < 2 {
+		return errors.New(usage)
+	}
+
+	rule, err := phase.ParseRule(*ruleName)
 	if err != nil {
 		return err
 	}
 
-	for _, row := range rows {
-		fmt.Println(row)
+	colorRunes := []rune(*colorNames)
+	if len(colorRunes) != 2 || colorRunes[0] == colorRunes[1] {
+		return fmt.Errorf("colors must be two different letters, got %s", *colorNames)
 	}
+	colors := [2]rune{colorRunes[0], colorRunes[1]}
 
-	return nil
+	fabricWidth, err := strconv.Atoi(args[0])
+	if err != nil {
+		return err
+	}
+
+	motifs, notes, err := parseMotifs(args[1:])
+	if err != nil {
+		return err
+	}
+
+	rowPairs, err := mosaic.Generate(fabricWidth, motifs, rule)
+	if err != nil {
+		return err
+	}
+
+	front, err := mosaic.Front(rowPairs, colors)
+	if err != nil {
+		return err
+	}
+
+	// Each line shows the knits and slips of a row pair next to how the
+	// front of the fabric looks
+	chart := rowPairs.Rotate180().ToStrings()
+	for i, line := range knitting.RenderColors(front, colorRunes, *ansi) {
+		rowPair := len(chart) - i
+		color := colors[(rowPair-1)%2]
+		fmt.Printf("%s  %s  %d (%c)\n", chart[i], line, rowPair, color)
+	}
+	printNotes(notes)
+
+	stacked := mosaic.StackedSlips(rowPairs)
+	if len(stacked) == 0 {
+		return nil
+	}
+
+	fmt.Printf("Slips taller than %d rows:\n", mosaic.MaxSlipHeight)
+	table := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "Row pair\tColumn\tHeight")
+	for _, slip := range stacked {
+		fmt.Fprintf(table, "%d\t%d\t%d\n", slip.RowPair, slip.Column, slip.Height)
+	}
+
+	return table.Flush()
+}
+
+func poolingCommand(args []string) error {
+	const usage = "usage: main.go pooling [--round] [--rows N] [--spread N] [--ansi] STITCHES YARN"
+
+	flags := flag.NewFlagSet("pooling", flag.ContinueOnError)
+	round := flags.Bool("round", false, "simulate circular knitting instead of flat knitting")
+	rows := flags.Int("rows", 0, "how many rows to preview, defaults to one full repeat")
+	spread := flags.Int("spread", 5, "how many stitches more or fewer to search for pooling patterns")
+	ansi := flags.Bool("ansi", false, "shade the preview with terminal colors")
+	if err := flags.Parse(args); err != nil {
+		return errors.New(usage)
+	}
+
+	args = flags.Args()
+	if len(args) < 2 {
+		return errors.New(usage)
+	}
+
+	stitches, err := strconv.Atoi(args[0])
+	if err != nil {
+		return err
+	}
+
+	yarn, err := pooling.ParseYarn(args[1])
+	if err != nil {
+		return err
+	}
+
+	if stitches < 1 {
+		return errors.New("stitches must be a positive integer")
+	}
+
+	previewRows := *rows
+	if previewRows == 0 {
+		previewRows = pooling.RepeatRows(yarn.Length(), stitches, *round)
+	}
+
+	chart, err := pooling.Simulate(yarn, stitches, previewRows, *round)
+	if err != nil {
+		return err
+	}
+
+	for _, line := range pooling.Render(chart, yarn, *ansi) {
+		fmt.Println(line)
+	}
+
+	kind := pooling.Classify(yarn.Length(), stitches, *round)
+	shift := pooling.Shift(yarn.Length(), stitches, *round)
+	fmt.Printf("%d stitches: %s (colors shift %d stitch(es))\n", stitches, kind, shift)
+
+	candidates := pooling.Search(yarn, stitches, *spread, *round)
+	if len(candidates) == 0 {
+		fmt.Printf("No stacked or argyle pooling within %d stitches\n", *spread)
+		return nil
+	}
+
+	fmt.Println("Nearby stitch counts that pool:")
+	table := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "Stitches\tPattern\tShift")
+	for _, candidate := range candidates {
+		fmt.Fprintf(table, "%d\t%s\t%d\n", candidate.Stitches, candidate.Kind, candidate.Shift)
+	}
+
+	return table.Flush()
 }
 
 func bracelet(args []string) error {
@@ -111,7 +1336,7 @@ func bracelet(args []string) error {
 }
 
 func main() {
-	const usage = "usage: main.go {knit-zigzag,knit-sync,bracelet-repeat} ARGS"
+	const usage = "usage: main.go {knit-zigzag,knit-analyze,knit-sync,knit-phase,knit-round,knit-shape,knit-explore,knit-solve,knit-plan,knit-follow,knit-colorwork,knit-mosaic,knit-double,knit-lace,knit-cable,knit-automaton,pooling,bracelet-repeat} ARGS"
 
 	if len(os.Args) < 2 {
 		fmt.Println(usage)
@@ -122,8 +1347,38 @@ func main() {
 	switch os.Args[1] {
 	case "knit-zigzag":
 		err = knitZigzag(os.Args[2:])
+	case "knit-analyze":
+		err = knitAnalyze(os.Args[2:])
 	case "knit-sync":
 		err = knitSync(os.Args[2:])
+	case "knit-phase":
+		err = knitPhase(os.Args[2:])
+	case "knit-round":
+		err = knitRound(os.Args[2:])
+	case "knit-shape":
+		err = knitShape(os.Args[2:])
+	case "knit-explore":
+		err = knitExplore(os.Args[2:])
+	case "knit-solve":
+		err = knitSolve(os.Args[2:])
+	case "knit-plan":
+		err = knitPlan(os.Args[2:])
+	case "knit-follow":
+		err = knitFollow(os.Args[2:])
+	case "knit-colorwork":
+		err = knitColorwork(os.Args[2:])
+	case "knit-mosaic":
+		err = knitMosaic(os.Args[2:])
+	case "knit-double":
+		err = knitDouble(os.Args[2:])
+	case "knit-lace":
+		err = knitLace(os.Args[2:])
+	case "knit-cable":
+		err = knitCable(os.Args[2:])
+	case "knit-automaton":
+		err = knitAutomaton(os.Args[2:])
+	case "pooling":
+		err = poolingCommand(os.Args[2:])
 	case "bracelet-repeat":
 		err = bracelet(os.Args[2:])
 	default:
@@ -0,0 +1,231 @@
+package pooling
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/ptrgags/mindless-stitchcraft/knitting"
+	"github.com/ptrgags/mindless-stitchcraft/stitchmath"
+)
+
+// A stretch of variegated yarn that is dyed a single color, measured in
+// how many stitches it lasts
+type ColorRun struct {
+	Color    rune
+	Stitches int
+}
+
+// The repeating color sequence of a variegated yarn
+type Yarn []ColorRun
+
+// Parse a color sequence like "R5 W3 B4 W3", where each color is a
+// single letter followed by how many stitches it lasts. Runs may be
+// separated by spaces or commas.
+func ParseYarn(yarn string) (Yarn, error) {
+	fields := strings.FieldsFunc(yarn, func(r rune) bool {
+		return unicode.IsSpace(r) || r == ','
+	})
+
+	if len(fields) == 0 {
+		return Yarn{}, errors.New("yarn must have at least one color")
+	}
+
+	result := make(Yarn, len(fields))
+	for i, field := range fields {
+		color, size := utf8.DecodeRuneInString(field)
+		if !unicode.IsLetter(color) {
+			return Yarn{}, fmt.Errorf("color run %s must start with a letter for the color", field)
+		}
+
+		stitches, err := strconv.Atoi(field[size:])
+		if err != nil || stitches < 1 {
+			return Yarn{}, fmt.Errorf("color run %s must end with a positive number of stitches", field)
+		}
+
+		result[i] = ColorRun{color, stitches}
+	}
+
+	return result, nil
+}
+
+// How many stitches until the color sequence repeats
+func (yarn Yarn) Length() int {
+	total := 0
+	for _, run := range yarn {
+		total += run.Stitches
+	}
+
+	return total
+}
+
+// The color of each stitch of one repeat of the yarn
+func (yarn Yarn) Colors() []rune {
+	result := make([]rune, 0, yarn.Length())
+	for _, run := range yarn {
+		for i := 0; i < run.Stitches; i++ {
+			result = append(result, run.Color)
+		}
+	}
+
+	return result
+}
+
+// How many rows until the colors line up the same way again. Flat work
+// needs an even number of rows to get back to the same side.
+func RepeatRows(yarnLength int, stitches int, round bool) int {
+	rows := int(stitchmath.LCM(uint(yarnLength), uint(stitches))) / stitches
+	if !round && rows%2 == 1 {
+		rows *= 2
+	}
+
+	return rows
+}
+
+// Simulate where each color lands in the fabric. The result is a chart
+// of the front of the fabric with the first row at the bottom, like the
+// knitting charts. Every row is worked from right to left on the front;
+// in flat work the fabric is turned at the end of every row, so every
+// second row is worked from left to right instead.
+func Simulate(yarn Yarn, stitches int, rows int, round bool) ([][]rune, error) {
+	if len(yarn) == 0 {
+		return nil, errors.New("yarn must have at least one color")
+	}
+
+	if stitches < 1 {
+		return nil, errors.New("stitches must be a positive integer")
+	}
+
+	if rows < 1 {
+		return nil, errors.New("rows must be a positive integer")
+	}
+
+	colors := yarn.Colors()
+
+	// Colors of each row in the order they are worked
+	worked := make([][]rune, rows)
+	for r := range worked {
+		worked[r] = make([]rune, stitches)
+		for i := range worked[r] {
+			worked[r][i] = colors[(r*stitches+i)%len(colors)]
+		}
+	}
+
+	if !round {
+		worked = knitting.ReverseAlternateRows(worked)
+	}
+
+	// Stitches worked right to left, and the first row at the bottom
+	chart := make([][]rune, rows)
+	for r, row := range worked {
+		reversed := slices.Clone(row)
+		slices.Reverse(reversed)
+		chart[rows-1-r] = reversed
+	}
+
+	return chart, nil
+}
+
+type Kind int
+
+const (
+	// The colors scatter without an obvious pattern
+	Scattered Kind = iota
+	// Each color stacks into vertical columns
+	Stacked
+	// The colors lean one way on right side rows and the other way on
+	// wrong side rows, making the classic argyle diamonds. Flat work only.
+	Argyle
+	// The colors spiral diagonally. Circular work only.
+	Diagonal
+)
+
+func (kind Kind) String() string {
+	switch kind {
+	case Stacked:
+		return "stacked"
+	case Argyle:
+		return "argyle"
+	case Diagonal:
+		return "diagonal"
+	default:
+		return "scattered"
+	}
+}
+
+// Colors that shift further than this each time they come around look
+// scattered rather than forming a pattern.
+const maxShift = 2
+
+// How far the colors move each time the knitter returns to the same
+// side of the fabric: every round for circular work, every two rows for
+// flat work. The shift is normalized to the smallest movement in either
+// direction.
+func Shift(yarnLength int, stitches int, round bool) int {
+	advance := stitches
+	if !round {
+		advance *= 2
+	}
+
+	shift := advance % yarnLength
+	if 2*shift > yarnLength {
+		shift -= yarnLength
+	}
+
+	return shift
+}
+
+// Classify the pooling pattern from how far the colors shift
+func Classify(yarnLength int, stitches int, round bool) Kind {
+	shift := Shift(yarnLength, stitches, round)
+	switch {
+	case shift == 0:
+		return Stacked
+	case shift >= -maxShift && shift <= maxShift && round:
+		return Diagonal
+	case shift >= -maxShift && shift <= maxShift:
+		return Argyle
+	default:
+		return Scattered
+	}
+}
+
+// A stitch count that makes the colors pool in a pattern
+type Candidate struct {
+	Stitches int
+	Shift    int
+	Kind     Kind
+}
+
+// Search stitch counts within spread stitches of the given count for
+// ones where the colors stack or make argyle (or diagonal) patterns.
+// Candidates are listed from the fewest to the most stitches.
+func Search(yarn Yarn, stitches int, spread int, round bool) []Candidate {
+	result := []Candidate{}
+	length := yarn.Length()
+	for count := max(stitches-spread, 1); count <= stitches+spread; count++ {
+		kind := Classify(length, count, round)
+		if kind == Scattered {
+			continue
+		}
+
+		result = append(result, Candidate{count, Shift(length, count, round), kind})
+	}
+
+	return result
+}
+
+// Render a chart as lines of color letters. If ansi is true, each
+// stitch is also shaded with a terminal background color.
+func Render(chart [][]rune, yarn Yarn, ansi bool) []string {
+	colors := make([]rune, len(yarn))
+	for i, run := range yarn {
+		colors[i] = run.Color
+	}
+
+	return knitting.RenderColors(chart, colors, ansi)
+}
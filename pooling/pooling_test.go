@@ -0,0 +1,181 @@
+package pooling
+
+import (
+	"testing"
+
+	"github.com/ptrgags/mindless-stitchcraft/checks"
+)
+
+func TestParseYarn(t *testing.T) {
+	t.Run("parses color runs", func(t *testing.T) {
+		result, err := ParseYarn("R5 W3, B4")
+
+		checks.CheckHasNoError(t, result, err)
+		expected := Yarn{{'R', 5}, {'W', 3}, {'B', 4}}
+		checks.CheckSlicesEqual(t, result, expected)
+	})
+
+	t.Run("empty yarn returns error", func(t *testing.T) {
+		result, err := ParseYarn(" ")
+
+		checks.CheckHasError(t, result, err, "yarn must have at least one color")
+	})
+
+	t.Run("missing color returns error", func(t *testing.T) {
+		result, err := ParseYarn("R5 3")
+
+		checks.CheckHasError(t, result, err, "color run 3 must start with a letter for the color")
+	})
+
+	t.Run("invalid count returns error", func(t *testing.T) {
+		result, err := ParseYarn("R5 W0")
+
+		checks.CheckHasError(t, result, err, "color run W0 must end with a positive number of stitches")
+	})
+}
+
+func TestYarn(t *testing.T) {
+	yarn := Yarn{{'R', 2}, {'B', 1}}
+
+	if yarn.Length() != 3 {
+		t.Errorf("expected length 3, got %d", yarn.Length())
+	}
+	checks.CheckSlicesEqual(t, yarn.Colors(), []rune("RRB"))
+}
+
+func TestRepeatRows(t *testing.T) {
+	cases := []struct {
+		label      string
+		yarnLength int
+		stitches   int
+		round      bool
+		expected   int
+	}{
+		{"flat, divides evenly", 6, 3, false, 2},
+		{"flat, odd row count is doubled", 6, 4, false, 6},
+		{"round, odd row count is kept", 6, 4, true, 3},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.label, func(t *testing.T) {
+			result := RepeatRows(tc.yarnLength, tc.stitches, tc.round)
+
+			if result != tc.expected {
+				t.Errorf("expected %d rows, got %d", tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestSimulate(t *testing.T) {
+	yarn := Yarn{{'A', 2}, {'B', 3}}
+
+	t.Run("invalid stitches returns error", func(t *testing.T) {
+		result, err := Simulate(yarn, 0, 2, false)
+
+		checks.CheckHasError(t, result, err, "stitches must be a positive integer")
+	})
+
+	t.Run("invalid rows returns error", func(t *testing.T) {
+		result, err := Simulate(yarn, 4, 0, false)
+
+		checks.CheckHasError(t, result, err, "rows must be a positive integer")
+	})
+
+	t.Run("flat work turns at the end of each row", func(t *testing.T) {
+		result, err := Simulate(yarn, 3, 2, false)
+
+		checks.CheckHasNoError(t, result, err)
+		// The rows are worked AAB then BBA
+		expected := [][]rune{
+			[]rune("BBA"),
+			[]rune("BAA"),
+		}
+		checks.CheckNestedSlicesEqual(t, result, expected)
+	})
+
+	t.Run("circular work always goes the same way", func(t *testing.T) {
+		result, err := Simulate(yarn, 3, 2, true)
+
+		checks.CheckHasNoError(t, result, err)
+		expected := [][]rune{
+			[]rune("ABB"),
+			[]rune("BAA"),
+		}
+		checks.CheckNestedSlicesEqual(t, result, expected)
+	})
+}
+
+func TestClassify(t *testing.T) {
+	cases := []struct {
+		label      string
+		yarnLength int
+		stitches   int
+		round      bool
+		expected   Kind
+		shift      int
+	}{
+		{"flat, one repeat per row stacks", 12, 12, false, Stacked, 0},
+		{"flat, half a repeat per row stacks", 12, 6, false, Stacked, 0},
+		{"flat, one stitch short is argyle", 12, 11, false, Argyle, -2},
+		{"flat, far from a repeat is scattered", 12, 9, false, Scattered, 6},
+		{"round, one repeat per round stacks", 12, 12, true, Stacked, 0},
+		{"round, one stitch over is diagonal", 12, 13, true, Diagonal, 1},
+		{"round, far from a repeat is scattered", 12, 17, true, Scattered, 5},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.label, func(t *testing.T) {
+			result := Classify(tc.yarnLength, tc.stitches, tc.round)
+			shift := Shift(tc.yarnLength, tc.stitches, tc.round)
+
+			if result != tc.expected || shift != tc.shift {
+				t.Errorf("expected %v with shift %d, got %v with shift %d", tc.expected, tc.shift, result, shift)
+			}
+		})
+	}
+}
+
+func TestSearch(t *testing.T) {
+	t.Run("finds stitch counts that pool", func(t *testing.T) {
+		yarn := Yarn{{'A', 6}, {'B', 6}}
+
+		result := Search(yarn, 10, 2, true)
+
+		expected := []Candidate{
+			{10, -2, Diagonal},
+			{11, -1, Diagonal},
+			{12, 0, Stacked},
+		}
+		checks.CheckSlicesEqual(t, result, expected)
+	})
+
+	t.Run("never searches below one stitch", func(t *testing.T) {
+		yarn := Yarn{{'A', 1}, {'B', 1}}
+
+		result := Search(yarn, 1, 3, false)
+
+		if result[0].Stitches != 1 {
+			t.Errorf("expected the search to start at 1 stitch, got %v", result)
+		}
+	})
+}
+
+func TestRender(t *testing.T) {
+	yarn := Yarn{{'A', 1}, {'B', 1}}
+	chart := [][]rune{[]rune("AB"), []rune("BA")}
+
+	t.Run("plain text uses the color letters", func(t *testing.T) {
+		result := Render(chart, yarn, false)
+
+		checks.CheckSlicesEqual(t, result, []string{"AB", "BA"})
+	})
+
+	t.Run("ansi output shades each stitch", func(t *testing.T) {
+		result := Render(chart, yarn, true)
+
+		if result[0] != "\x1b[41mA\x1b[47mB\x1b[0m" {
+			t.Errorf("unexpected ansi output %q", result[0])
+		}
+	})
+}
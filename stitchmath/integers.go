@@ -0,0 +1,24 @@
+package stitchmath
+
+// Compute the greatest common divisor of a and b. gcd(a, 0) = a
+func GCD(a uint, b uint) uint {
+	if b > a {
+		return GCD(b, a)
+	}
+
+	if b == 0 {
+		return a
+	}
+
+	return GCD(b, a%b)
+}
+
+// Compute the least common multiple of a and b. If either is 0, the
+// result is 0
+func LCM(a uint, b uint) uint {
+	if a == 0 || b == 0 {
+		return 0
+	}
+
+	return a * b / GCD(a, b)
+}
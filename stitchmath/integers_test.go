@@ -0,0 +1,53 @@
+package stitchmath
+
+import "testing"
+
+func TestGCD(t *testing.T) {
+	cases := []struct {
+		label    string
+		a        uint
+		b        uint
+		expected uint
+	}{
+		{"gcd with zero is the other number", 6, 0, 6},
+		{"gcd of zero and a number is the number", 0, 6, 6},
+		{"coprime numbers have gcd 1", 9, 10, 1},
+		{"shared factors", 12, 18, 6},
+		{"order does not matter", 18, 12, 6},
+		{"multiple returns smaller number", 3, 9, 3},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.label, func(t *testing.T) {
+			result := GCD(tc.a, tc.b)
+
+			if result != tc.expected {
+				t.Errorf("Expected %v, got %v", tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestLCM(t *testing.T) {
+	cases := []struct {
+		label    string
+		a        uint
+		b        uint
+		expected uint
+	}{
+		{"lcm with zero is zero", 6, 0, 0},
+		{"coprime numbers multiply", 4, 5, 20},
+		{"shared factors", 4, 6, 12},
+		{"multiple returns larger number", 3, 9, 9},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.label, func(t *testing.T) {
+			result := LCM(tc.a, tc.b)
+
+			if result != tc.expected {
+				t.Errorf("Expected %v, got %v", tc.expected, result)
+			}
+		})
+	}
+}
@@ -73,22 +73,6 @@ func (perm Permutation) Apply(value uint) uint {
 	return perm.values[value]
 }
 
-func gcd(a uint, b uint) uint {
-	if b > a {
-		return gcd(b, a)
-	}
-
-	if b == 0 {
-		return a
-	}
-
-	return gcd(b, a%b)
-}
-
-func lcm(a uint, b uint) uint {
-	return a * b / gcd(a, b)
-}
-
 func findCycle(values []uint, start_index int, visited []bool) []uint {
 	n := len(values)
 	// The longest possible cycles use N elements.
@@ -149,7 +133,7 @@ func (perm Permutation) Order() uint {
 	order := uint(1)
 	for _, cycle := range cycles {
 		cycleLength := uint(len(cycle))
-		order = lcm(order, cycleLength)
+		order = LCM(order, cycleLength)
 	}
 
 	return order